@@ -0,0 +1,75 @@
+// Command sts-server vends temporary credentials of the
+// given roles in the ECS metadata format, e.g.,
+//
+//	sts-server -addr 127.0.0.1:8090 -role dev=acs:ram::123:role/dev
+//
+// serves the credentials of role dev at
+// http://127.0.0.1:8090/latest/meta-data/ram/security-credentials/dev
+// and http://127.0.0.1:8090/credentials/dev.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/practigo/aliyun"
+	"github.com/practigo/aliyun/sts"
+)
+
+type roles map[string]*sts.AssumeRoleParam
+
+func (rs roles) String() string {
+	return fmt.Sprint(map[string]*sts.AssumeRoleParam(rs))
+}
+
+func (rs roles) Set(v string) error {
+	kv := strings.SplitN(v, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("want name=roleArn, got %s", v)
+	}
+	rs[kv[0]] = &sts.AssumeRoleParam{RoleArn: kv[1]}
+	return nil
+}
+
+func main() {
+	var (
+		addr    = flag.String("addr", "127.0.0.1:8090", "listen address")
+		host    = flag.String("host", sts.Host, "STS endpoint")
+		session = flag.String("session", "sts-server", "role session name")
+		dir     = flag.String("cache", "", "credentials cache dir, default to the user cache dir")
+		rs      = roles{}
+	)
+	flag.Var(rs, "role", "role to serve as name=roleArn, repeatable")
+	flag.Parse()
+
+	requiredVars := []string{"STS_KEY_ID", "STS_KEY_SECRET"}
+	for _, k := range requiredVars {
+		if os.Getenv(k) == "" {
+			log.Fatalf("require env setting: %v", requiredVars)
+		}
+	}
+	if len(rs) == 0 {
+		log.Fatal("require at least one -role")
+	}
+	for _, p := range rs {
+		p.RoleSessionName = *session
+	}
+
+	store, err := sts.NewFileStore(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := aliyun.NewAccessKey(os.Getenv("STS_KEY_ID"), os.Getenv("STS_KEY_SECRET"))
+	srv := &sts.Server{
+		Getter: sts.WrapStore(sts.New(s, *host), nil, store),
+		Roles:  rs,
+	}
+
+	log.Printf("serving %d role(s) on %s", len(rs), *addr)
+	log.Fatal(http.ListenAndServe(*addr, srv))
+}
@@ -0,0 +1,189 @@
+package live
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Protocols of the stream URLs.
+const (
+	RTMP = "rtmp"
+	FLV  = "flv"
+	HLS  = "hls"
+)
+
+// Path returns the path of the stream for the protocol,
+// e.g., /{App}/{Stream}.flv for FLV.
+func (uri StreamURI) Path(protocol string) string {
+	p := "/" + uri.App + "/" + uri.Stream
+	switch protocol {
+	case FLV:
+		p += ".flv"
+	case HLS:
+		p += ".m3u8"
+	}
+	return p
+}
+
+// URL returns the unsigned URL of the stream for the
+// protocol, rtmp:// for RTMP and http:// for the others.
+func (uri StreamURI) URL(protocol string) *url.URL {
+	scheme := "http"
+	if protocol == RTMP {
+		scheme = "rtmp"
+	}
+	return &url.URL{
+		Scheme: scheme,
+		Host:   uri.Domain,
+		Path:   uri.Path(protocol),
+	}
+}
+
+// URL auth types.
+const (
+	// AuthA appends auth_key={ts}-{rand}-{uid}-{md5(path-ts-rand-uid-key)}
+	// to the query, where ts is the Unix expiry time.
+	AuthA = "A"
+	// AuthB prefixes the path with /{YYYYMMDDHHMM}/{md5(key+ts+path)},
+	// where ts is the issue time in UTC+8.
+	AuthB = "B"
+	// AuthC prefixes the path with /{md5(key+path+ts)}/{ts},
+	// where ts is the hex Unix issue time.
+	AuthC = "C"
+)
+
+// Errors of the URL auth verification.
+var (
+	ErrAuthMissing = errors.New("live: auth missing or malformed")
+	ErrAuthInvalid = errors.New("live: auth invalid")
+	ErrAuthExpired = errors.New("live: auth expired")
+)
+
+// the time zone of AuthB
+var cst = time.FixedZone("UTC+8", 8*3600)
+
+const timeFormatB = "200601021504"
+
+// A URLAuth signs & verifies the stream URLs with the
+// auth key configured for the domain.
+type URLAuth struct {
+	Key string
+	// Type is one of AuthA (default), AuthB or AuthC.
+	Type string
+	// TTL is the validity configured for the domain, as
+	// AuthB & AuthC sign the issue time instead of the
+	// expiry time; not used by AuthA.
+	TTL time.Duration
+	// Rand & UID are for AuthA, default to "0".
+	Rand string
+	UID  string
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+// Sign returns the signed u valid until the expire.
+func (a *URLAuth) Sign(u *url.URL, expire time.Time) string {
+	s := *u // keep the u intact
+	path := s.Path
+
+	switch a.Type {
+	case AuthB:
+		ts := expire.Add(-a.TTL).In(cst).Format(timeFormatB)
+		s.Path = "/" + ts + "/" + md5Hex(a.Key+ts+path) + path
+	case AuthC:
+		ts := strconv.FormatInt(expire.Add(-a.TTL).Unix(), 16)
+		s.Path = "/" + md5Hex(a.Key+path+ts) + "/" + ts + path
+	default:
+		ts := strconv.FormatInt(expire.Unix(), 10)
+		r, uid := orZero(a.Rand), orZero(a.UID)
+		q := s.Query()
+		q.Set("auth_key", strings.Join([]string{ts, r, uid,
+			md5Hex(strings.Join([]string{path, ts, r, uid, a.Key}, "-"))}, "-"))
+		s.RawQuery = q.Encode()
+	}
+	return s.String()
+}
+
+// PushURL returns the signed RTMP URL to publish the stream.
+func (a *URLAuth) PushURL(uri StreamURI, expire time.Time) string {
+	return a.Sign(uri.URL(RTMP), expire)
+}
+
+// PlayURL returns the signed URL to play the stream with
+// the protocol; the Domain of the uri should be the play
+// domain.
+func (a *URLAuth) PlayURL(uri StreamURI, protocol string, expire time.Time) string {
+	return a.Sign(uri.URL(protocol), expire)
+}
+
+// Verify verifies the auth of the u at the time now, and
+// returns the original path without the auth, e.g., for an
+// edge proxy to forward.
+func (a *URLAuth) Verify(u *url.URL, now time.Time) (path string, err error) {
+	var expire time.Time
+	var hash, want string
+
+	switch a.Type {
+	case AuthB, AuthC:
+		segs := strings.SplitN(u.Path, "/", 4) // "", x, y, path
+		if len(segs) != 4 {
+			return "", ErrAuthMissing
+		}
+		path = "/" + segs[3]
+		if a.Type == AuthB {
+			ts := segs[1]
+			t, err := time.ParseInLocation(timeFormatB, ts, cst)
+			if err != nil {
+				return "", ErrAuthMissing
+			}
+			expire, hash, want = t.Add(a.TTL), segs[2], md5Hex(a.Key+ts+path)
+		} else {
+			ts := segs[2]
+			sec, err := strconv.ParseInt(ts, 16, 64)
+			if err != nil {
+				return "", ErrAuthMissing
+			}
+			expire, hash, want = time.Unix(sec, 0).Add(a.TTL), segs[1], md5Hex(a.Key+path+ts)
+		}
+	default:
+		path = u.Path
+		parts := strings.Split(u.Query().Get("auth_key"), "-")
+		if len(parts) < 4 { // the rand may contain "-"
+			return "", ErrAuthMissing
+		}
+		n := len(parts)
+		ts := parts[0]
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return "", ErrAuthMissing
+		}
+		r, uid := strings.Join(parts[1:n-2], "-"), parts[n-2]
+		expire, hash, want = time.Unix(sec, 0), parts[n-1],
+			md5Hex(strings.Join([]string{path, ts, r, uid, a.Key}, "-"))
+	}
+
+	if subtle.ConstantTimeCompare([]byte(strings.ToLower(hash)), []byte(want)) != 1 {
+		return "", ErrAuthInvalid
+	}
+	if now.After(expire) {
+		return "", fmt.Errorf("%w at %s", ErrAuthExpired, expire.Format(time.RFC3339))
+	}
+	return path, nil
+}
@@ -0,0 +1,58 @@
+package live_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/practigo/aliyun/live"
+)
+
+func TestURLAuthA(t *testing.T) {
+	// the example of the CDN doc
+	a := &live.URLAuth{Key: "aliyuncdnexp1234"}
+	u, _ := url.Parse("http://cdn.example.com/video/standard/1K.html")
+	got := a.Sign(u, time.Unix(1444435200, 0))
+	want := "http://cdn.example.com/video/standard/1K.html?auth_key=1444435200-0-0-80cd3862d699b7118eed99103f2a3a4f"
+	if got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestURLAuth(t *testing.T) {
+	expire := time.Now().Add(time.Hour)
+	for _, typ := range []string{live.AuthA, live.AuthB, live.AuthC} {
+		a := &live.URLAuth{Key: "secret", Type: typ, TTL: 30 * time.Minute}
+		for _, raw := range []string{
+			a.PushURL(testURI, expire),
+			a.PlayURL(testURI, live.FLV, expire),
+			a.PlayURL(testURI, live.HLS, expire),
+		} {
+			u, err := url.Parse(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path, err := a.Verify(u, time.Now())
+			if err != nil {
+				t.Errorf("%s: %s should be valid: %v", typ, raw, err)
+				continue
+			}
+			if !strings.HasPrefix(path, testURI.Path(live.RTMP)) {
+				t.Errorf("%s: unexpected path %s", typ, path)
+			}
+
+			if _, err = a.Verify(u, expire.Add(2*time.Minute)); !errors.Is(err, live.ErrAuthExpired) {
+				t.Errorf("%s: want expired, got %v", typ, err)
+			}
+			other := &live.URLAuth{Key: "other", Type: typ, TTL: a.TTL}
+			if _, err = other.Verify(u, time.Now()); err != live.ErrAuthInvalid {
+				t.Errorf("%s: want invalid, got %v", typ, err)
+			}
+		}
+		if _, err := a.Verify(testURI.URL(live.FLV), time.Now()); err == nil {
+			t.Errorf("%s: unsigned URL should be rejected", typ)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package live
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Actions of the stream callbacks.
+const (
+	ActionPublish     = "publish"
+	ActionPublishDone = "publish_done"
+)
+
+// Headers of the callback signature.
+const (
+	HeaderSignature = "ALI-LIVE-SIGNATURE"
+	HeaderTimestamp = "ALI-LIVE-TIMESTAMP"
+)
+
+// A StreamEvent is a publish or publish_done callback.
+type StreamEvent struct {
+	Action string
+	StreamURI
+	// IP is the client IP of the publisher.
+	IP       string
+	Time     time.Time
+	Node     string
+	UserArgs string
+}
+
+// A RecordEvent is a record callback, posted when a record
+// file is created (or the recording state changes if the
+// Event is set).
+type RecordEvent struct {
+	Domain string `json:"domain"`
+	App    string `json:"app"`
+	Stream string `json:"stream"`
+	Event  string `json:"event,omitempty"`
+	// URI is the OSS object of the record file.
+	URI       string  `json:"uri"`
+	Duration  float64 `json:"duration"`
+	StartTime int64   `json:"start_time"`
+	StopTime  int64   `json:"stop_time"`
+}
+
+// StreamURI returns the stream of the record.
+func (e *RecordEvent) StreamURI() StreamURI {
+	return StreamURI{Domain: e.Domain, App: e.App, Stream: e.Stream}
+}
+
+// Errors of the callback signature verification.
+var (
+	ErrCallbackSignature = errors.New("live: invalid callback signature")
+	ErrCallbackTimestamp = errors.New("live: callback timestamp out of range")
+)
+
+// CallbackSignature returns md5(domain|timestamp|key), the
+// signature of the callbacks to the domain.
+func CallbackSignature(domain, timestamp, key string) string {
+	return md5Hex(domain + "|" + timestamp + "|" + key)
+}
+
+// A CallbackHandler is a http.Handler for the stream &
+// record callbacks, which dispatches the typed events to
+// the handlers. An error of a handler responds 500 so that
+// Live may retry.
+type CallbackHandler struct {
+	// Key, if not empty, is the auth key to verify the
+	// signature of the callbacks.
+	Key string
+	// Domain is the domain of the callback URL to sign,
+	// default to the Host of the request.
+	Domain string
+	// MaxSkew, if positive, rejects the callbacks with a
+	// timestamp too far from now.
+	MaxSkew time.Duration
+
+	OnPublish     func(StreamEvent) error
+	OnPublishDone func(StreamEvent) error
+	OnRecord      func(RecordEvent) error
+}
+
+func (h *CallbackHandler) verify(r *http.Request) error {
+	ts := r.Header.Get(HeaderTimestamp)
+	domain := h.Domain
+	if domain == "" {
+		domain = r.Host
+	}
+	want := CallbackSignature(domain, ts, h.Key)
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(HeaderSignature)), []byte(want)) != 1 {
+		return ErrCallbackSignature
+	}
+	if h.MaxSkew > 0 {
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return ErrCallbackTimestamp
+		}
+		if d := time.Since(time.Unix(sec, 0)); d > h.MaxSkew || d < -h.MaxSkew {
+			return ErrCallbackTimestamp
+		}
+	}
+	return nil
+}
+
+func parseStreamEvent(r *http.Request) (e StreamEvent, err error) {
+	e = StreamEvent{
+		Action: r.Form.Get("action"),
+		StreamURI: StreamURI{
+			Domain: r.Form.Get("app"),
+			App:    r.Form.Get("appname"),
+			Stream: r.Form.Get("id"),
+		},
+		IP:       r.Form.Get("ip"),
+		Node:     r.Form.Get("node"),
+		UserArgs: r.Form.Get("usrargs"),
+	}
+	if t := r.Form.Get("time"); t != "" {
+		sec, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return e, fmt.Errorf("live: callback time %q: %w", t, err)
+		}
+		e.Time = time.Unix(sec, 0)
+	}
+	return
+}
+
+// ServeHTTP handles the stream callbacks by the action in
+// the query (or form), and the record callbacks by the
+// JSON body otherwise.
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.Key != "" {
+		if err := h.verify(r); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	switch action := r.Form.Get("action"); action {
+	case ActionPublish, ActionPublishDone:
+		e, perr := parseStreamEvent(r)
+		if perr != nil {
+			http.Error(w, perr.Error(), http.StatusBadRequest)
+			return
+		}
+		f := h.OnPublish
+		if action == ActionPublishDone {
+			f = h.OnPublishDone
+		}
+		if f != nil {
+			err = f(e)
+		}
+	case "":
+		var e RecordEvent
+		if derr := json.NewDecoder(r.Body).Decode(&e); derr != nil {
+			http.Error(w, "live: bad record callback: "+derr.Error(), http.StatusBadRequest)
+			return
+		}
+		if h.OnRecord != nil {
+			err = h.OnRecord(e)
+		}
+	default:
+		http.Error(w, "live: unknown callback action "+action, http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
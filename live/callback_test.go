@@ -0,0 +1,73 @@
+package live_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/practigo/aliyun/live"
+)
+
+func TestCallbackHandler(t *testing.T) {
+	var events []string
+	h := &live.CallbackHandler{
+		Key:     "secret",
+		MaxSkew: time.Minute,
+		OnPublish: func(e live.StreamEvent) error {
+			if e.StreamURI != testURI || e.IP != "10.0.0.1" || e.Time.Unix() != 1600000000 {
+				t.Errorf("unexpected event %+v", e)
+			}
+			events = append(events, e.Action)
+			return nil
+		},
+		OnPublishDone: func(e live.StreamEvent) error {
+			events = append(events, e.Action)
+			return errors.New("handler error")
+		},
+		OnRecord: func(e live.RecordEvent) error {
+			if e.StreamURI() != testURI || e.Duration != 69.403 {
+				t.Errorf("unexpected record %+v", e)
+			}
+			events = append(events, "record")
+			return nil
+		},
+	}
+
+	const query = "?ip=10.0.0.1&id=stream&app=live.example.com&appname=app&time=1600000000&usrargs=&node=edge"
+	const record = `{"domain":"live.example.com","app":"app","stream":"stream","uri":"record/app/stream/0.flv","duration":69.403,"start_time":1488985786,"stop_time":1488985840}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	for _, c := range []struct {
+		method, target, body, ts, sig string
+		want                          int
+	}{
+		{"GET", "/cb" + query + "&action=publish", "", now, "", http.StatusOK},
+		{"GET", "/cb" + query + "&action=publish_done", "", now, "", http.StatusInternalServerError},
+		{"POST", "/cb", record, now, "", http.StatusOK},
+		{"GET", "/cb" + query + "&action=unknown", "", now, "", http.StatusBadRequest},
+		{"POST", "/cb", "not json", now, "", http.StatusBadRequest},
+		{"GET", "/cb" + query + "&action=publish", "", now, "bad", http.StatusForbidden},
+		{"GET", "/cb" + query + "&action=publish", "", old, "", http.StatusForbidden},
+	} {
+		r := httptest.NewRequest(c.method, "http://callback.example.com"+c.target, strings.NewReader(c.body))
+		if c.sig == "" {
+			c.sig = live.CallbackSignature("callback.example.com", c.ts, h.Key)
+		}
+		r.Header.Set(live.HeaderTimestamp, c.ts)
+		r.Header.Set(live.HeaderSignature, c.sig)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != c.want {
+			t.Errorf("%s %s: want %d, got %d %s", c.method, c.target, c.want, w.Code, w.Body)
+		}
+	}
+
+	if got := strings.Join(events, ","); got != "publish,publish_done,record" {
+		t.Errorf("unexpected events %s", got)
+	}
+}
@@ -0,0 +1,57 @@
+package live
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/practigo/aliyun"
+)
+
+// A Client sends the Live APIs to an endpoint.
+type Client struct {
+	signer aliyun.Signer
+	host   string
+	cl     *http.Client
+}
+
+// NewClient returns a Client sending to the host, e.g.,
+// Host or a regional endpoint. A nil cl defaults to a
+// 10s-timeout HTTP client.
+func NewClient(s aliyun.Signer, host string, cl *http.Client) *Client {
+	if cl == nil {
+		cl = aliyun.TimeoutClient(10 * time.Second)
+	}
+	return &Client{
+		signer: s,
+		host:   host,
+		cl:     cl,
+	}
+}
+
+func (c *Client) do(a aliyun.API, resp interface{}) error {
+	return aliyun.Get(c.cl, c.signer, a, c.host, resp)
+}
+
+// DescribeRecords sends a DescribeRecordsAPI.
+func (c *Client) DescribeRecords(uri StreamURI, start, end time.Time) (resp DescribeRecordsResponse, err error) {
+	err = c.do(DescribeRecordsAPI(uri, start, end), &resp)
+	return
+}
+
+// CreateRecord sends a CreateRecordAPI.
+func (c *Client) CreateRecord(uri StreamURI, start, end time.Time, oss aliyun.OSS) (resp CreateRecordResponse, err error) {
+	err = c.do(CreateRecordAPI(uri, start, end, oss), &resp)
+	return
+}
+
+// DescribeRecordContent sends a DescribeRecordContentAPI.
+func (c *Client) DescribeRecordContent(uri StreamURI, start, end time.Time) (resp DescribeContentResponse, err error) {
+	err = c.do(DescribeRecordContentAPI(uri, start, end), &resp)
+	return
+}
+
+// defaultClient is the Client of the package-level
+// functions, kept as before.
+func defaultClient(s aliyun.Signer) *Client {
+	return &Client{signer: s, host: Host, cl: http.DefaultClient}
+}
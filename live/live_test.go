@@ -0,0 +1,139 @@
+package live_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/practigo/aliyun"
+	"github.com/practigo/aliyun/live"
+)
+
+var testURI = live.StreamURI{
+	Domain: "live.example.com",
+	App:    "app",
+	Stream: "stream",
+}
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("Action") != "DescribeLiveStreamRecordIndexFiles" || q.Get("StreamName") != testURI.Stream {
+			t.Errorf("unexpected query %v", q)
+		}
+		fmt.Fprint(w, `{"RequestId":"test","RecordIndexInfoList":{"RecordIndexInfo":[{"RecordId":"r1","Duration":10.5}]}}`)
+	}))
+	defer srv.Close()
+
+	c := live.NewClient(aliyun.NewAccessKey("id", "secret"), srv.URL, nil)
+	resp, err := c.DescribeRecords(testURI, time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := resp.List.Files; len(files) != 1 || files[0].RecordID != "r1" || files[0].Duration != 10.5 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestEachOnlineStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("Action") != "DescribeLiveStreamsOnlineList" || q.Get("DomainName") != testURI.Domain {
+			t.Errorf("unexpected query %v", q)
+		}
+		page := q.Get("PageNum")
+		fmt.Fprintf(w, `{"RequestId":"test","PageNum":%s,"PageSize":1,"TotalNum":3,"TotalPage":3,
+			"OnlineInfo":{"LiveStreamOnlineInfo":[{"DomainName":"%s","AppName":"app","StreamName":"s%s",
+			"PublishTime":"2020-09-13T12:26:40Z","ClientIp":"10.0.0.1","Transcoded":"no"}]}}`,
+			page, testURI.Domain, page)
+	}))
+	defer srv.Close()
+
+	c := live.NewClient(aliyun.NewAccessKey("id", "secret"), srv.URL, nil)
+	var streams []string
+	err := c.EachOnlineStream(live.StreamsRequest{
+		StreamURI: live.StreamURI{Domain: testURI.Domain},
+		PageSize:  1,
+	}, func(s live.OnlineStream) error {
+		if s.ClientIP != "10.0.0.1" || s.PublishTime.Unix() != 1600000000 {
+			t.Errorf("unexpected stream %+v", s)
+		}
+		streams = append(streams, s.Stream)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(streams); got != "[s1 s2 s3]" {
+		t.Errorf("unexpected streams %s", got)
+	}
+}
+
+func TestStreamAPIs(t *testing.T) {
+	start, end := time.Unix(1600000000, 0), time.Unix(1600003600, 0)
+	r := &live.StreamsRequest{
+		StreamURI: testURI,
+		QueryType: live.QueryStrict,
+		Start:     start,
+		End:       end,
+		Page:      2,
+		PageSize:  20,
+	}
+
+	// the page keys differ between the two lists
+	for api, want := range map[aliyun.API]map[string]string{
+		live.DescribeOnlineStreamsAPI(r): {
+			"Action":     "DescribeLiveStreamsOnlineList",
+			"PageNum":    "2",
+			"PageNumber": "",
+		},
+		live.DescribePublishStreamsAPI(r): {
+			"Action":     "DescribeLiveStreamsPublishList",
+			"PageNumber": "2",
+			"PageNum":    "",
+		},
+	} {
+		want["DomainName"] = testURI.Domain
+		want["AppName"] = testURI.App
+		want["StreamName"] = testURI.Stream
+		want["QueryType"] = live.QueryStrict
+		want["StartTime"] = "2020-09-13T12:26:40Z"
+		want["EndTime"] = "2020-09-13T13:26:40Z"
+		want["PageSize"] = "20"
+		v := api.Param()
+		for k, w := range want {
+			if got := v.Get(k); got != w {
+				t.Errorf("%s %s: want %q, got %q", want["Action"], k, w, got)
+			}
+		}
+	}
+
+	for api, want := range map[aliyun.API]map[string]string{
+		live.ForbidStreamAPI(testURI, true, end): {
+			"Action":     "ForbidLiveStream",
+			"Oneshot":    "yes",
+			"ResumeTime": "2020-09-13T13:26:40Z",
+		},
+		live.ForbidStreamAPI(testURI, false, time.Time{}): {
+			"Action":     "ForbidLiveStream",
+			"Oneshot":    "",
+			"ResumeTime": "",
+		},
+		live.ResumeStreamAPI(testURI): {
+			"Action": "ResumeLiveStream",
+		},
+	} {
+		want["DomainName"] = testURI.Domain
+		want["AppName"] = testURI.App
+		want["StreamName"] = testURI.Stream
+		want["LiveStreamType"] = "publisher"
+		v := api.Param()
+		for k, w := range want {
+			if got := v.Get(k); got != w {
+				t.Errorf("%s %s: want %q, got %q", want["Action"], k, w, got)
+			}
+		}
+	}
+}
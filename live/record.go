@@ -1,7 +1,6 @@
 package live
 
 import (
-	"net/http"
 	"net/url"
 	"time"
 
@@ -104,26 +103,20 @@ type DescribeContentResponse struct {
 	} `json:"RecordContentInfoList"`
 }
 
-// DescribeRecords uses the signer to send a DescribeRecordsAPI.
-func DescribeRecords(s aliyun.Signer, uri StreamURI, start, end time.Time) (resp DescribeRecordsResponse, err error) {
-	api := DescribeRecordsAPI(uri, start, end)
-
-	err = aliyun.Get(http.DefaultClient, s, api, Host, &resp)
-	return
+// DescribeRecords uses the signer to send a DescribeRecordsAPI
+// to Host with http.DefaultClient; see Client for more control.
+func DescribeRecords(s aliyun.Signer, uri StreamURI, start, end time.Time) (DescribeRecordsResponse, error) {
+	return defaultClient(s).DescribeRecords(uri, start, end)
 }
 
-// CreateRecord uses the signer to send a CreateRecordAPI.
-func CreateRecord(s aliyun.Signer, uri StreamURI, start, end time.Time, oss aliyun.OSS) (resp CreateRecordResponse, err error) {
-	api := CreateRecordAPI(uri, start, end, oss)
-
-	err = aliyun.Get(http.DefaultClient, s, api, Host, &resp)
-	return
+// CreateRecord uses the signer to send a CreateRecordAPI
+// to Host with http.DefaultClient; see Client for more control.
+func CreateRecord(s aliyun.Signer, uri StreamURI, start, end time.Time, oss aliyun.OSS) (CreateRecordResponse, error) {
+	return defaultClient(s).CreateRecord(uri, start, end, oss)
 }
 
-// DescribeRecordContent uses the signer to send a DescribeRecordsAPI.
-func DescribeRecordContent(s aliyun.Signer, uri StreamURI, start, end time.Time) (resp DescribeContentResponse, err error) {
-	api := DescribeRecordContentAPI(uri, start, end)
-
-	err = aliyun.Get(http.DefaultClient, s, api, Host, &resp)
-	return
+// DescribeRecordContent uses the signer to send a DescribeRecordContentAPI
+// to Host with http.DefaultClient; see Client for more control.
+func DescribeRecordContent(s aliyun.Signer, uri StreamURI, start, end time.Time) (DescribeContentResponse, error) {
+	return defaultClient(s).DescribeRecordContent(uri, start, end)
 }
@@ -0,0 +1,264 @@
+package live
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/practigo/aliyun"
+)
+
+// Record formats.
+const (
+	RecordM3U8 = "m3u8"
+	RecordFLV  = "flv"
+	RecordMP4  = "mp4"
+)
+
+// Placeholders of the record object naming templates,
+// e.g., record/{AppName}/{StreamName}/{EscapedStartTime}_{EscapedEndTime}.
+const (
+	PlaceholderApp       = "{AppName}"
+	PlaceholderStream    = "{StreamName}"
+	PlaceholderSequence  = "{Sequence}"
+	PlaceholderStartTime = "{EscapedStartTime}"
+	PlaceholderEndTime   = "{EscapedEndTime}"
+	PlaceholderUnixTime  = "{UnixTimestamp}"
+)
+
+// Cycle durations of a record file in seconds.
+const (
+	MinCycleDuration = 15 * 60
+	MaxCycleDuration = 6 * 60 * 60
+)
+
+// On-demand modes of a record config.
+const (
+	RecordAlways    = 0 // record every stream
+	RecordByHTTP    = 1 // ask the HTTP callback on publish
+	RecordByCommand = 7 // record on RealTimeRecordCommand
+)
+
+// Commands of RealTimeRecordCommand.
+const (
+	RecordStart        = "start"
+	RecordStop         = "stop"
+	RecordCancelDelete = "cancel_delete"
+)
+
+// A RecordFormat is a record output of a record config.
+type RecordFormat struct {
+	Format string `json:"Format"`
+	// OssObjectPrefix is the naming template of the record
+	// files (the playlist for m3u8), without the extension.
+	OssObjectPrefix string `json:"OssObjectPrefix"`
+	// SliceOssObjectPrefix is the naming template of the
+	// TS segments, mandatory for m3u8.
+	SliceOssObjectPrefix string `json:"SliceOssObjectPrefix,omitempty"`
+	// CycleDuration is the duration of a record file in
+	// seconds, MaxCycleDuration if not set.
+	CycleDuration int `json:"CycleDuration,omitempty"`
+}
+
+// Validate checks the format on the client side.
+func (f *RecordFormat) Validate() error {
+	switch f.Format {
+	case RecordM3U8:
+		if f.SliceOssObjectPrefix == "" {
+			return fmt.Errorf("live: %s record wants SliceOssObjectPrefix", f.Format)
+		}
+	case RecordFLV, RecordMP4:
+	default:
+		return fmt.Errorf("live: unknown record format %q", f.Format)
+	}
+	if f.OssObjectPrefix == "" {
+		return fmt.Errorf("live: %s record wants OssObjectPrefix", f.Format)
+	}
+	if d := f.CycleDuration; d != 0 && (d < MinCycleDuration || d > MaxCycleDuration) {
+		return fmt.Errorf("live: cycle duration %d, want %d to %d seconds",
+			d, MinCycleDuration, MaxCycleDuration)
+	}
+	return nil
+}
+
+// A RecordConfig records the streams of an app (or a single
+// stream if the Stream is set) to the OSS bucket; the Object
+// of the OSS is not used.
+type RecordConfig struct {
+	StreamURI
+	OSS     aliyun.OSS
+	Formats []RecordFormat
+	// OnDemand is one of RecordAlways (default),
+	// RecordByHTTP or RecordByCommand.
+	OnDemand int
+	// Start & End, if set, limit the recording period.
+	Start time.Time
+	End   time.Time
+}
+
+// AddRecordConfigAPI returns the API for AddLiveAppRecordConfig.
+// The formats are validated before.
+func AddRecordConfigAPI(c *RecordConfig) (aliyun.API, error) {
+	if len(c.Formats) == 0 {
+		return nil, fmt.Errorf("live: no record format")
+	}
+	for i := range c.Formats {
+		if err := c.Formats[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	a := &api{v: url.Values{}}
+
+	// api-specific
+	a.v.Add("Action", "AddLiveAppRecordConfig")
+	a.v.Add("DomainName", c.Domain)
+	a.v.Add("AppName", c.App)
+	a.v.Add("OssEndpoint", c.OSS.Endpoint)
+	a.v.Add("OssBucket", c.OSS.Bucket)
+	for i, f := range c.Formats {
+		n := "RecordFormat." + strconv.Itoa(i+1) + "."
+		a.v.Add(n+"Format", f.Format)
+		a.v.Add(n+"OssObjectPrefix", f.OssObjectPrefix)
+		if f.SliceOssObjectPrefix != "" {
+			a.v.Add(n+"SliceOssObjectPrefix", f.SliceOssObjectPrefix)
+		}
+		if f.CycleDuration > 0 {
+			a.v.Add(n+"CycleDuration", strconv.Itoa(f.CycleDuration))
+		}
+	}
+
+	// optional
+	if c.Stream != "" {
+		a.v.Add("StreamName", c.Stream)
+	}
+	if c.OnDemand != RecordAlways {
+		a.v.Add("OnDemand", strconv.Itoa(c.OnDemand))
+	}
+	if !c.Start.IsZero() {
+		a.v.Add("StartTime", aliyun.FormatT(c.Start))
+	}
+	if !c.End.IsZero() {
+		a.v.Add("EndTime", aliyun.FormatT(c.End))
+	}
+
+	return a, nil
+}
+
+// DescribeRecordConfigAPI returns the API for DescribeLiveRecordConfig.
+// The App & Stream of the uri are optional; page starts from 1.
+func DescribeRecordConfigAPI(uri StreamURI, page, size int) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	// api-specific
+	a.v.Add("Action", "DescribeLiveRecordConfig")
+	a.v.Add("DomainName", uri.Domain)
+
+	// optional
+	if uri.App != "" {
+		a.v.Add("AppName", uri.App)
+	}
+	if uri.Stream != "" {
+		a.v.Add("StreamName", uri.Stream)
+	}
+	if page > 0 {
+		a.v.Add("PageNum", strconv.Itoa(page))
+	}
+	if size > 0 {
+		a.v.Add("PageSize", strconv.Itoa(size))
+	}
+
+	return a
+}
+
+// DeleteRecordConfigAPI returns the API for DeleteLiveAppRecordConfig.
+// The Stream of the uri is optional.
+func DeleteRecordConfigAPI(uri StreamURI) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	// api-specific
+	a.v.Add("Action", "DeleteLiveAppRecordConfig")
+	a.v.Add("DomainName", uri.Domain)
+	a.v.Add("AppName", uri.App)
+
+	// optional
+	if uri.Stream != "" {
+		a.v.Add("StreamName", uri.Stream)
+	}
+
+	return a
+}
+
+// RecordCommandAPI returns the API for RealTimeRecordCommand,
+// which starts or stops recording the stream on demand, i.e.,
+// with a RecordByCommand config.
+func RecordCommandAPI(uri StreamURI, command string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	// api-specific
+	a.v.Add("Action", "RealTimeRecordCommand")
+	fillURI(a.v, uri)
+	a.v.Add("Command", command)
+
+	return a
+}
+
+// An AppRecordConfig is a record config in DescribeLiveRecordConfig.
+type AppRecordConfig struct {
+	StreamURI
+	OssEndpoint      string `json:"OssEndpoint"`
+	OssBucket        string `json:"OssBucket"`
+	CreateTime       string `json:"CreateTime"`
+	StartTime        string `json:"StartTime"`
+	EndTime          string `json:"EndTime"`
+	OnDemand         int    `json:"OnDemond"` // sic
+	RecordFormatList struct {
+		RecordFormat []RecordFormat `json:"RecordFormat"`
+	} `json:"RecordFormatList"`
+}
+
+// A DescribeRecordConfigResponse is the response for
+// DescribeLiveRecordConfig.
+type DescribeRecordConfigResponse struct {
+	Page
+	List struct {
+		Configs []AppRecordConfig `json:"LiveAppRecord"`
+	} `json:"LiveAppRecordList"`
+	RequestID string `json:"RequestId"`
+}
+
+// A RecordConfigResponse is the response for
+// AddLiveAppRecordConfig, DeleteLiveAppRecordConfig &
+// RealTimeRecordCommand.
+type RecordConfigResponse struct {
+	RequestID string `json:"RequestId"`
+}
+
+// AddRecordConfig sends an AddRecordConfigAPI.
+func (c *Client) AddRecordConfig(rc *RecordConfig) (resp RecordConfigResponse, err error) {
+	api, err := AddRecordConfigAPI(rc)
+	if err != nil {
+		return
+	}
+	err = c.do(api, &resp)
+	return
+}
+
+// DescribeRecordConfig sends a DescribeRecordConfigAPI.
+func (c *Client) DescribeRecordConfig(uri StreamURI, page, size int) (resp DescribeRecordConfigResponse, err error) {
+	err = c.do(DescribeRecordConfigAPI(uri, page, size), &resp)
+	return
+}
+
+// DeleteRecordConfig sends a DeleteRecordConfigAPI.
+func (c *Client) DeleteRecordConfig(uri StreamURI) (resp RecordConfigResponse, err error) {
+	err = c.do(DeleteRecordConfigAPI(uri), &resp)
+	return
+}
+
+// RecordCommand sends a RecordCommandAPI.
+func (c *Client) RecordCommand(uri StreamURI, command string) (resp RecordConfigResponse, err error) {
+	err = c.do(RecordCommandAPI(uri, command), &resp)
+	return
+}
@@ -0,0 +1,57 @@
+package live_test
+
+import (
+	"testing"
+
+	"github.com/practigo/aliyun"
+	"github.com/practigo/aliyun/live"
+)
+
+func TestAddRecordConfigAPI(t *testing.T) {
+	c := &live.RecordConfig{
+		StreamURI: live.StreamURI{Domain: testURI.Domain, App: testURI.App},
+		OSS:       aliyun.OSS{Bucket: "example-bucket", Endpoint: "oss-cn-hangzhou.aliyuncs.com"},
+		Formats: []live.RecordFormat{{
+			Format:               live.RecordM3U8,
+			OssObjectPrefix:      "record/{AppName}/{StreamName}/{EscapedStartTime}_{EscapedEndTime}",
+			SliceOssObjectPrefix: "record/{AppName}/{StreamName}/{UnixTimestamp}_{Sequence}",
+			CycleDuration:        3600,
+		}, {
+			Format:          live.RecordMP4,
+			OssObjectPrefix: "record/{AppName}/{StreamName}/{EscapedStartTime}_{EscapedEndTime}",
+		}},
+		OnDemand: live.RecordByCommand,
+	}
+	api, err := live.AddRecordConfigAPI(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := api.Param()
+	for k, want := range map[string]string{
+		"Action":                              "AddLiveAppRecordConfig",
+		"OssBucket":                           "example-bucket",
+		"RecordFormat.1.Format":               "m3u8",
+		"RecordFormat.1.CycleDuration":        "3600",
+		"RecordFormat.2.Format":               "mp4",
+		"RecordFormat.2.SliceOssObjectPrefix": "",
+		"OnDemand":                            "7",
+		"StreamName":                          "",
+	} {
+		if got := v.Get(k); got != want {
+			t.Errorf("%s: want %q, got %q", k, want, got)
+		}
+	}
+
+	for name, f := range map[string]func(*live.RecordFormat){
+		"unknown format": func(f *live.RecordFormat) { f.Format = "ts" },
+		"no slice":       func(f *live.RecordFormat) { f.SliceOssObjectPrefix = "" },
+		"short cycle":    func(f *live.RecordFormat) { f.CycleDuration = 60 },
+	} {
+		bad := *c
+		bad.Formats = append([]live.RecordFormat(nil), c.Formats...)
+		f(&bad.Formats[0])
+		if _, err := live.AddRecordConfigAPI(&bad); err == nil {
+			t.Errorf("%s: should be invalid", name)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package live
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/practigo/aliyun"
+)
+
+// Query types of the stream lists.
+const (
+	QueryFuzzy  = "fuzzy"
+	QueryStrict = "strict"
+)
+
+// Stream types of the stream lists.
+const (
+	StreamAll   = "all"
+	StreamRaw   = "raw"
+	StreamTrans = "trans"
+)
+
+// LiveStreamType of ForbidLiveStream & ResumeLiveStream.
+const publisher = "publisher"
+
+// A StreamsRequest contains the param for the stream lists.
+// The Domain of the StreamURI is mandatory; the App & Stream
+// filter the list by the QueryType.
+type StreamsRequest struct {
+	StreamURI
+	QueryType  string
+	StreamType string
+	// Start & End are optional for the online list, and
+	// mandatory for the publish list.
+	Start time.Time
+	End   time.Time
+	// Page starts from 1.
+	Page     int
+	PageSize int
+}
+
+func fillStreamsRequest(v url.Values, r *StreamsRequest, pageKey string) {
+	v.Add("DomainName", r.Domain)
+
+	// optional
+	if r.App != "" {
+		v.Add("AppName", r.App)
+	}
+	if r.Stream != "" {
+		v.Add("StreamName", r.Stream)
+	}
+	if r.QueryType != "" {
+		v.Add("QueryType", r.QueryType)
+	}
+	if r.StreamType != "" {
+		v.Add("StreamType", r.StreamType)
+	}
+	if !r.Start.IsZero() {
+		v.Add("StartTime", aliyun.FormatT(r.Start))
+	}
+	if !r.End.IsZero() {
+		v.Add("EndTime", aliyun.FormatT(r.End))
+	}
+	if r.Page > 0 {
+		v.Add(pageKey, strconv.Itoa(r.Page))
+	}
+	if r.PageSize > 0 {
+		v.Add("PageSize", strconv.Itoa(r.PageSize))
+	}
+}
+
+// DescribeOnlineStreamsAPI returns the API for DescribeLiveStreamsOnlineList.
+func DescribeOnlineStreamsAPI(r *StreamsRequest) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	// api-specific
+	a.v.Add("Action", "DescribeLiveStreamsOnlineList")
+	fillStreamsRequest(a.v, r, "PageNum")
+
+	return a
+}
+
+// DescribePublishStreamsAPI returns the API for DescribeLiveStreamsPublishList.
+func DescribePublishStreamsAPI(r *StreamsRequest) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	// api-specific
+	a.v.Add("Action", "DescribeLiveStreamsPublishList")
+	fillStreamsRequest(a.v, r, "PageNumber")
+
+	return a
+}
+
+// ForbidStreamAPI returns the API for ForbidLiveStream, which
+// disconnects the publisher and forbids it until the resume
+// time (forever if zero). With oneshot, the publisher is only
+// disconnected.
+func ForbidStreamAPI(uri StreamURI, oneshot bool, resume time.Time) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	// api-specific
+	a.v.Add("Action", "ForbidLiveStream")
+	fillURI(a.v, uri)
+	a.v.Add("LiveStreamType", publisher)
+
+	// optional
+	if oneshot {
+		a.v.Add("Oneshot", "yes")
+	}
+	if !resume.IsZero() {
+		a.v.Add("ResumeTime", aliyun.FormatT(resume))
+	}
+
+	return a
+}
+
+// ResumeStreamAPI returns the API for ResumeLiveStream.
+func ResumeStreamAPI(uri StreamURI) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	// api-specific
+	a.v.Add("Action", "ResumeLiveStream")
+	fillURI(a.v, uri)
+	a.v.Add("LiveStreamType", publisher)
+
+	return a
+}
+
+// A Page is the pagination of the stream lists.
+type Page struct {
+	PageNum   int `json:"PageNum"`
+	PageSize  int `json:"PageSize"`
+	TotalNum  int `json:"TotalNum"`
+	TotalPage int `json:"TotalPage"`
+}
+
+// An OnlineStream is a stream being published.
+type OnlineStream struct {
+	StreamURI
+	PublishTime   time.Time `json:"PublishTime"`
+	PublishURL    string    `json:"PublishUrl"`
+	PublishDomain string    `json:"PublishDomain"`
+	PublishType   string    `json:"PublishType"`
+	ClientIP      string    `json:"ClientIp"`
+	ServerIP      string    `json:"ServerIp"`
+	// Transcoded is "yes" for a transcoded stream of the
+	// TranscodeID (template).
+	Transcoded  string `json:"Transcoded"`
+	TranscodeID string `json:"TranscodeId"`
+	// video related
+	VideoCodecID  int `json:"VideoCodecId"`
+	AudioCodecID  int `json:"AudioCodecId"`
+	Width         int `json:"Width"`
+	Height        int `json:"Height"`
+	FrameRate     int `json:"FrameRate"`
+	VideoDataRate int `json:"VideoDataRate"`
+	AudioDataRate int `json:"AudioDataRate"`
+}
+
+// A PublishedStream is a record of stream publishing.
+type PublishedStream struct {
+	StreamURI
+	StreamURL     string    `json:"StreamUrl"`
+	PublishURL    string    `json:"PublishUrl"`
+	PublishDomain string    `json:"PublishDomain"`
+	PublishType   string    `json:"PublishType"`
+	PublishTime   time.Time `json:"PublishTime"`
+	// StopTime is empty if still publishing.
+	StopTime     string `json:"StopTime"`
+	ClientAddr   string `json:"ClientAddr"`
+	EdgeNodeAddr string `json:"EdgeNodeAddr"`
+	Transcoded   string `json:"Transcoded"`
+	TranscodeID  string `json:"TranscodeId"`
+}
+
+// A DescribeOnlineStreamsResponse is the response for
+// DescribeLiveStreamsOnlineList.
+type DescribeOnlineStreamsResponse struct {
+	Page
+	List struct {
+		Streams []OnlineStream `json:"LiveStreamOnlineInfo"`
+	} `json:"OnlineInfo"`
+	RequestID string `json:"RequestId"`
+}
+
+// A DescribePublishStreamsResponse is the response for
+// DescribeLiveStreamsPublishList.
+type DescribePublishStreamsResponse struct {
+	Page
+	List struct {
+		Streams []PublishedStream `json:"LiveStreamPublishInfo"`
+	} `json:"PublishInfo"`
+	RequestID string `json:"RequestId"`
+}
+
+// A ForbidStreamResponse is the response for
+// ForbidLiveStream & ResumeLiveStream.
+type ForbidStreamResponse struct {
+	RequestID string `json:"RequestId"`
+}
+
+// DescribeOnlineStreams sends a DescribeOnlineStreamsAPI.
+func (c *Client) DescribeOnlineStreams(r *StreamsRequest) (resp DescribeOnlineStreamsResponse, err error) {
+	err = c.do(DescribeOnlineStreamsAPI(r), &resp)
+	return
+}
+
+// DescribePublishStreams sends a DescribePublishStreamsAPI.
+func (c *Client) DescribePublishStreams(r *StreamsRequest) (resp DescribePublishStreamsResponse, err error) {
+	err = c.do(DescribePublishStreamsAPI(r), &resp)
+	return
+}
+
+// ForbidStream sends a ForbidStreamAPI.
+func (c *Client) ForbidStream(uri StreamURI, oneshot bool, resume time.Time) (resp ForbidStreamResponse, err error) {
+	err = c.do(ForbidStreamAPI(uri, oneshot, resume), &resp)
+	return
+}
+
+// ResumeStream sends a ResumeStreamAPI.
+func (c *Client) ResumeStream(uri StreamURI) (resp ForbidStreamResponse, err error) {
+	err = c.do(ResumeStreamAPI(uri), &resp)
+	return
+}
+
+// EachOnlineStream calls f for every online stream of the
+// request page by page, starting from r.Page, until f
+// returns an error.
+func (c *Client) EachOnlineStream(r StreamsRequest, f func(OnlineStream) error) error {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	for {
+		resp, err := c.DescribeOnlineStreams(&r)
+		if err != nil {
+			return err
+		}
+		for _, s := range resp.List.Streams {
+			if err = f(s); err != nil {
+				return err
+			}
+		}
+		if r.Page >= resp.TotalPage || len(resp.List.Streams) == 0 {
+			return nil
+		}
+		r.Page++
+	}
+}
@@ -0,0 +1,184 @@
+package mts
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/practigo/aliyun"
+)
+
+// DefaultRetryDelay is the initial delay to retry a
+// throttled submission.
+const DefaultRetryDelay = time.Second
+
+// IsThrottled checks if the err is caused by flow control,
+// which is worth retrying later.
+func IsThrottled(err error) bool {
+	ce, ok := err.(*aliyun.CanonicalizedError)
+	return ok && (strings.HasPrefix(ce.Code, "Throttling") || ce.Code == "ServiceUnavailable")
+}
+
+// A BulkResult is the result of a single submission.
+type BulkResult struct {
+	// Index is the position of the request in the stream.
+	Index int
+	// JobIDs are the ids of the submitted jobs, one per output.
+	JobIDs []string
+	// Err is either the request error or the first *JobError
+	// of the outputs.
+	Err error
+}
+
+// A BulkSubmitter submits a stream of requests with bounded
+// concurrency and rate, e.g., for backfills.
+type BulkSubmitter struct {
+	Submitter Submitter
+	// Concurrency is the max number of in-flight
+	// submissions, 1 if not set.
+	Concurrency int
+	// Interval is the min interval between two submissions
+	// (including retries); no rate limit if not set.
+	Interval time.Duration
+	// Retries is the max number of retries of a throttled
+	// submission, with backoff starting from RetryDelay
+	// (DefaultRetryDelay if not set).
+	Retries    int
+	RetryDelay time.Duration
+	// Checkpoint, if not nil, is called with the results in
+	// the submission order, i.e., all the requests before
+	// the Index are done. Persist Index+1 to resume by
+	// skipping as many requests next time.
+	Checkpoint func(BulkResult)
+}
+
+// wait waits for the rate limiter or the ctx.
+func wait(ctx context.Context, tick <-chan time.Time) error {
+	if tick == nil {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-tick:
+		return nil
+	}
+}
+
+func (b *BulkSubmitter) submit(ctx context.Context, i int, r *SubmitJobsRequest, tick <-chan time.Time) (res BulkResult) {
+	res.Index = i
+	delay := b.RetryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+
+	for attempt := 0; ; attempt++ {
+		if res.Err = wait(ctx, tick); res.Err != nil {
+			return
+		}
+
+		resp, err := b.Submitter.Submit(r)
+		if err == nil {
+			for _, jr := range resp.List.Result {
+				res.JobIDs = append(res.JobIDs, jr.Job.JobID)
+				if res.Err == nil {
+					res.Err = jr.Err()
+				}
+			}
+			return
+		}
+		if !IsThrottled(err) || attempt >= b.Retries {
+			res.Err = err
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			res.Err = ctx.Err()
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// Submit submits the requests from reqs until it's closed
+// or the ctx is done, and returns the results in the order
+// of the requests. Once the ctx is done, Submit stops
+// reading reqs and returns the results of the requests
+// already read.
+func (b *BulkSubmitter) Submit(ctx context.Context, reqs <-chan *SubmitJobsRequest) []BulkResult {
+	n := b.Concurrency
+	if n <= 0 {
+		n = 1
+	}
+	var tick <-chan time.Time
+	if b.Interval > 0 {
+		t := time.NewTicker(b.Interval)
+		defer t.Stop()
+		tick = t.C
+	}
+
+	type item struct {
+		i int
+		r *SubmitJobsRequest
+	}
+	items := make(chan item)
+	go func() {
+		defer close(items)
+		for i := 0; ; i++ {
+			var r *SubmitJobsRequest
+			select {
+			case req, ok := <-reqs:
+				if !ok {
+					return
+				}
+				r = req
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case items <- item{i, r}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	out := make(chan BulkResult)
+	var wg sync.WaitGroup
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for it := range items {
+				out <- b.submit(ctx, it.i, it.r, tick)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	var results []BulkResult
+	next := 0 // the next index to checkpoint
+	done := make(map[int]bool)
+	for res := range out {
+		for len(results) <= res.Index {
+			results = append(results, BulkResult{})
+		}
+		results[res.Index] = res
+		done[res.Index] = true
+
+		for done[next] {
+			if b.Checkpoint != nil {
+				b.Checkpoint(results[next])
+			}
+			delete(done, next)
+			next++
+		}
+	}
+	return results
+}
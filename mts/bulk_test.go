@@ -0,0 +1,102 @@
+package mts_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/practigo/aliyun"
+	"github.com/practigo/aliyun/mts"
+)
+
+// fakeSubmitter throttles the first submission of every
+// even request, and fails the request "bad".
+type fakeSubmitter struct {
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func (s *fakeSubmitter) Submit(r *mts.SubmitJobsRequest) (resp mts.SubmitJobsResponse, err error) {
+	s.mu.Lock()
+	s.attempts[r.Input]++
+	n := s.attempts[r.Input]
+	s.mu.Unlock()
+
+	var i int
+	fmt.Sscan(r.Input, &i)
+	if i%2 == 0 && n == 1 {
+		return resp, &aliyun.CanonicalizedError{Code: "Throttling.User"}
+	}
+	if r.Input == "bad" {
+		return resp, &aliyun.CanonicalizedError{Code: aliyun.ErrCodeInvalidParameter}
+	}
+
+	// sleep a bit to shuffle the completions
+	time.Sleep(time.Duration(10-i%10) * time.Millisecond)
+	resp.List.Result = []mts.JobResult{{Success: true, Job: mts.JobInfo{JobID: "job-" + r.Input}}}
+	return
+}
+
+func TestBulkSubmitter(t *testing.T) {
+	s := &fakeSubmitter{attempts: make(map[string]int)}
+	var checkpoints []int
+	b := &mts.BulkSubmitter{
+		Submitter:   s,
+		Concurrency: 4,
+		Interval:    time.Millisecond,
+		Retries:     1,
+		RetryDelay:  time.Millisecond,
+		Checkpoint:  func(r mts.BulkResult) { checkpoints = append(checkpoints, r.Index) },
+	}
+
+	reqs := make(chan *mts.SubmitJobsRequest)
+	go func() {
+		for i := 1; i <= 20; i++ {
+			reqs <- &mts.SubmitJobsRequest{Input: fmt.Sprint(i)}
+		}
+		reqs <- &mts.SubmitJobsRequest{Input: "bad"}
+		close(reqs)
+	}()
+
+	results := b.Submit(context.Background(), reqs)
+	if len(results) != 21 {
+		t.Fatalf("want 21 results, got %d", len(results))
+	}
+	for i, r := range results[:20] {
+		if r.Index != i || r.Err != nil || r.JobIDs[0] != fmt.Sprint("job-", i+1) {
+			t.Errorf("unexpected result %d: %+v", i, r)
+		}
+	}
+	if results[20].Err == nil || mts.IsThrottled(results[20].Err) {
+		t.Errorf("want an invalid param error, got %v", results[20].Err)
+	}
+	for i, c := range checkpoints {
+		if c != i {
+			t.Fatalf("checkpoints out of order: %v", checkpoints)
+		}
+	}
+	if len(checkpoints) != 21 {
+		t.Errorf("want 21 checkpoints, got %d", len(checkpoints))
+	}
+}
+
+func TestBulkSubmitterCancelIdle(t *testing.T) {
+	b := &mts.BulkSubmitter{Submitter: &fakeSubmitter{attempts: make(map[string]int)}}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan []mts.BulkResult)
+	go func() {
+		done <- b.Submit(ctx, make(chan *mts.SubmitJobsRequest)) // idle & never closed
+	}()
+	select {
+	case results := <-done:
+		if len(results) != 0 {
+			t.Errorf("want no results, got %v", results)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Submit should return once the ctx is done")
+	}
+}
@@ -0,0 +1,319 @@
+package mts
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/practigo/aliyun"
+)
+
+// A Submitter submits a transcoding job.
+type Submitter interface {
+	Submit(*SubmitJobsRequest) (SubmitJobsResponse, error)
+}
+
+// A Querier queries a transcoding job.
+type Querier interface {
+	Query(id string, rest ...string) (QueryJobsResponse, error)
+}
+
+// A Transcoder wraps a Submitter & a Querier.
+type Transcoder interface {
+	Submitter
+	Querier
+}
+
+// A JobManager operates the transcoding jobs.
+type JobManager interface {
+	// Cancel cancels a job not yet started.
+	Cancel(id string) (CancelJobResponse, error)
+	// List lists the jobs by state, pipeline and time range.
+	// See EachJob for iterating all the pages.
+	List(*ListJobRequest) (ListJobResponse, error)
+	// ListByPipeline lists the jobs of the pipeline like List,
+	// with the PipelineID mandatory.
+	ListByPipeline(*ListJobRequest) (ListJobResponse, error)
+	// Search searches the jobs page by page.
+	Search(*SearchJobRequest) (SearchJobResponse, error)
+}
+
+// A PipelineManager manages the pipelines.
+type PipelineManager interface {
+	AddPipeline(*Pipeline) (PipelineResponse, error)
+	UpdatePipeline(*Pipeline) (PipelineResponse, error)
+	QueryPipelines(id string, rest ...string) (QueryPipelinesResponse, error)
+	SearchPipelines(state string, page, size int) (SearchPipelinesResponse, error)
+	DeletePipeline(id string) (DeletePipelineResponse, error)
+}
+
+// A TemplateManager manages the transcoding templates.
+type TemplateManager interface {
+	AddTemplate(*Template) (TemplateResponse, error)
+	UpdateTemplate(*Template) (TemplateResponse, error)
+	QueryTemplates(id string, rest ...string) (QueryTemplatesResponse, error)
+	SearchTemplates(state, prefix string, page, size int) (SearchTemplatesResponse, error)
+	DeleteTemplate(id string) (DeleteTemplateResponse, error)
+}
+
+// A WaterMarkManager manages the watermark templates.
+type WaterMarkManager interface {
+	AddWaterMarkTemplate(*WaterMarkTemplate) (WaterMarkTemplateResponse, error)
+	UpdateWaterMarkTemplate(*WaterMarkTemplate) (WaterMarkTemplateResponse, error)
+	QueryWaterMarkTemplates(id string, rest ...string) (QueryWaterMarkTemplatesResponse, error)
+	DeleteWaterMarkTemplate(id string) (DeleteWaterMarkTemplateResponse, error)
+}
+
+// A SnapshotJobManager submits & queries the snapshot jobs.
+type SnapshotJobManager interface {
+	SubmitSnapshotJob(*SubmitSnapshotJobRequest) (SubmitSnapshotJobResponse, error)
+	QuerySnapshotJobs(id string, rest ...string) (QuerySnapshotJobsResponse, error)
+}
+
+// A MediaInfoJobManager submits & queries the media info jobs.
+type MediaInfoJobManager interface {
+	SubmitMediaInfoJob(*SubmitMediaInfoJobRequest) (SubmitMediaInfoJobResponse, error)
+	QueryMediaInfoJobs(id string, rest ...string) (QueryMediaInfoJobsResponse, error)
+}
+
+// A WorkflowManager manages the media workflows.
+type WorkflowManager interface {
+	AddMediaWorkflow(name string, t *Topology, triggerMode string) (MediaWorkflowResponse, error)
+	UpdateMediaWorkflow(id string, t *Topology) (MediaWorkflowResponse, error)
+	QueryMediaWorkflows(id string, rest ...string) (QueryMediaWorkflowsResponse, error)
+	DeactivateMediaWorkflow(id string) (MediaWorkflowResponse, error)
+	ListWorkflowExecutions(*ListWorkflowExecutionsRequest) (ListWorkflowExecutionsResponse, error)
+}
+
+// A MediaTagger tags the medias of the media library.
+type MediaTagger interface {
+	AddMediaTag(mediaID, tag string) (AddMediaTagResponse, error)
+}
+
+// A Client provides all the MTS APIs of this package.
+type Client interface {
+	Transcoder
+	JobManager
+	PipelineManager
+	TemplateManager
+	WaterMarkManager
+	SnapshotJobManager
+	MediaInfoJobManager
+	WorkflowManager
+	MediaTagger
+}
+
+type client struct {
+	signer aliyun.Signer
+	host   string
+	cl     *http.Client
+	// options
+	region   string
+	pipeline string
+	mws      []func(http.RoundTripper) http.RoundTripper
+}
+
+func (c *client) do(a aliyun.API, resp interface{}) error {
+	if c.region != "" || c.pipeline != "" {
+		a = &defaultAPI{API: a, region: c.region, pipeline: c.pipeline}
+	}
+	return aliyun.Get(c.cl, c.signer, a, c.host, resp)
+}
+
+func (c *client) Submit(r *SubmitJobsRequest) (resp SubmitJobsResponse, err error) {
+	err = c.do(SubmitJobsAPI(r), &resp)
+	return
+}
+
+func (c *client) Query(id string, rest ...string) (resp QueryJobsResponse, err error) {
+	err = c.do(QueryJobsAPI(id, rest...), &resp)
+	return
+}
+
+func (c *client) Cancel(id string) (resp CancelJobResponse, err error) {
+	err = c.do(CancelJobAPI(id), &resp)
+	return
+}
+
+func (c *client) List(r *ListJobRequest) (resp ListJobResponse, err error) {
+	err = c.do(ListJobAPI(r), &resp)
+	return
+}
+
+func (c *client) ListByPipeline(r *ListJobRequest) (resp ListJobResponse, err error) {
+	err = c.do(QueryJobListByPipelineAPI(r), &resp)
+	return
+}
+
+func (c *client) Search(r *SearchJobRequest) (resp SearchJobResponse, err error) {
+	err = c.do(SearchJobAPI(r), &resp)
+	return
+}
+
+func (c *client) AddPipeline(p *Pipeline) (resp PipelineResponse, err error) {
+	err = c.do(AddPipelineAPI(p), &resp)
+	return
+}
+
+func (c *client) UpdatePipeline(p *Pipeline) (resp PipelineResponse, err error) {
+	err = c.do(UpdatePipelineAPI(p), &resp)
+	return
+}
+
+func (c *client) QueryPipelines(id string, rest ...string) (resp QueryPipelinesResponse, err error) {
+	err = c.do(QueryPipelinesAPI(id, rest...), &resp)
+	return
+}
+
+func (c *client) SearchPipelines(state string, page, size int) (resp SearchPipelinesResponse, err error) {
+	err = c.do(SearchPipelinesAPI(state, page, size), &resp)
+	return
+}
+
+func (c *client) DeletePipeline(id string) (resp DeletePipelineResponse, err error) {
+	err = c.do(DeletePipelineAPI(id), &resp)
+	return
+}
+
+func (c *client) AddTemplate(t *Template) (resp TemplateResponse, err error) {
+	err = c.do(AddTemplateAPI(t), &resp)
+	return
+}
+
+func (c *client) UpdateTemplate(t *Template) (resp TemplateResponse, err error) {
+	err = c.do(UpdateTemplateAPI(t), &resp)
+	return
+}
+
+func (c *client) QueryTemplates(id string, rest ...string) (resp QueryTemplatesResponse, err error) {
+	err = c.do(QueryTemplatesAPI(id, rest...), &resp)
+	return
+}
+
+func (c *client) SearchTemplates(state, prefix string, page, size int) (resp SearchTemplatesResponse, err error) {
+	err = c.do(SearchTemplatesAPI(state, prefix, page, size), &resp)
+	return
+}
+
+func (c *client) DeleteTemplate(id string) (resp DeleteTemplateResponse, err error) {
+	err = c.do(DeleteTemplateAPI(id), &resp)
+	return
+}
+
+func (c *client) AddWaterMarkTemplate(t *WaterMarkTemplate) (resp WaterMarkTemplateResponse, err error) {
+	err = c.do(AddWaterMarkTemplateAPI(t), &resp)
+	return
+}
+
+func (c *client) UpdateWaterMarkTemplate(t *WaterMarkTemplate) (resp WaterMarkTemplateResponse, err error) {
+	err = c.do(UpdateWaterMarkTemplateAPI(t), &resp)
+	return
+}
+
+func (c *client) QueryWaterMarkTemplates(id string, rest ...string) (resp QueryWaterMarkTemplatesResponse, err error) {
+	err = c.do(QueryWaterMarkTemplatesAPI(id, rest...), &resp)
+	return
+}
+
+func (c *client) DeleteWaterMarkTemplate(id string) (resp DeleteWaterMarkTemplateResponse, err error) {
+	err = c.do(DeleteWaterMarkTemplateAPI(id), &resp)
+	return
+}
+
+func (c *client) SubmitSnapshotJob(r *SubmitSnapshotJobRequest) (resp SubmitSnapshotJobResponse, err error) {
+	api, err := SubmitSnapshotJobAPI(r)
+	if err != nil {
+		return
+	}
+	err = c.do(api, &resp)
+	return
+}
+
+func (c *client) QuerySnapshotJobs(id string, rest ...string) (resp QuerySnapshotJobsResponse, err error) {
+	err = c.do(QuerySnapshotJobsAPI(id, rest...), &resp)
+	return
+}
+
+func (c *client) SubmitMediaInfoJob(r *SubmitMediaInfoJobRequest) (resp SubmitMediaInfoJobResponse, err error) {
+	api, err := SubmitMediaInfoJobAPI(r)
+	if err != nil {
+		return
+	}
+	err = c.do(api, &resp)
+	return
+}
+
+func (c *client) QueryMediaInfoJobs(id string, rest ...string) (resp QueryMediaInfoJobsResponse, err error) {
+	err = c.do(QueryMediaInfoJobsAPI(id, rest...), &resp)
+	return
+}
+
+func (c *client) AddMediaWorkflow(name string, t *Topology, triggerMode string) (resp MediaWorkflowResponse, err error) {
+	api, err := AddMediaWorkflowAPI(name, t, triggerMode)
+	if err != nil {
+		return
+	}
+	err = c.do(api, &resp)
+	return
+}
+
+func (c *client) UpdateMediaWorkflow(id string, t *Topology) (resp MediaWorkflowResponse, err error) {
+	api, err := UpdateMediaWorkflowAPI(id, t)
+	if err != nil {
+		return
+	}
+	err = c.do(api, &resp)
+	return
+}
+
+func (c *client) QueryMediaWorkflows(id string, rest ...string) (resp QueryMediaWorkflowsResponse, err error) {
+	err = c.do(QueryMediaWorkflowsAPI(id, rest...), &resp)
+	return
+}
+
+func (c *client) DeactivateMediaWorkflow(id string) (resp MediaWorkflowResponse, err error) {
+	err = c.do(DeactivateMediaWorkflowAPI(id), &resp)
+	return
+}
+
+func (c *client) ListWorkflowExecutions(r *ListWorkflowExecutionsRequest) (resp ListWorkflowExecutionsResponse, err error) {
+	err = c.do(ListWorkflowExecutionsAPI(r), &resp)
+	return
+}
+
+func (c *client) AddMediaTag(mediaID, tag string) (resp AddMediaTagResponse, err error) {
+	err = c.do(AddMediaTagAPI(mediaID, tag), &resp)
+	return
+}
+
+// EachJob calls f for each job listed by the request r,
+// following the NextPageToken until the last page or
+// f returns an error.
+func EachJob(m JobManager, r ListJobRequest, f func(JobInfo) error) error {
+	for {
+		resp, err := m.List(&r)
+		if err != nil {
+			return err
+		}
+		for _, j := range resp.JobList.Job {
+			if err = f(j); err != nil {
+				return err
+			}
+		}
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		r.NextPageToken = resp.NextPageToken
+	}
+}
+
+// New returns a new Client with a 10s-timeout
+// HTTP client, configured by the opts.
+func New(s aliyun.Signer, host string, opts ...Option) Client {
+	c := &client{
+		signer: s,
+		host:   host,
+		cl:     aliyun.TimeoutClient(10 * time.Second),
+	}
+	c.apply(opts)
+	return c
+}
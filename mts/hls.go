@@ -0,0 +1,166 @@
+package mts
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+)
+
+// Container formats for the packaging outputs.
+const (
+	FormatM3U8 = "m3u8"
+	FormatMPD  = "mpd"
+)
+
+// Encryption settings; MTS supports only HLS AES-128
+// with either a Base64 plain key or a KMS data key.
+const (
+	EncryptionHLSAES128 = "hls-aes-128"
+	KeyTypeBase64       = "Base64"
+	KeyTypeKMS          = "KMS"
+)
+
+// Segment durations in seconds.
+const (
+	MinSegmentDuration = 1
+	MaxSegmentDuration = 60
+)
+
+// An OutSubtitle extracts a subtitle stream of the input
+// to a file, where the Map selects the stream, e.g., "0:3".
+type OutSubtitle struct {
+	Map             string `json:"Map"`
+	OutSubtitleFile JobIO  `json:"OutSubtitleFile"`
+}
+
+// An ExtSubtitle is an external subtitle file to be
+// burnt into the output.
+type ExtSubtitle struct {
+	Input    JobIO  `json:"Input"`
+	CharEnc  string `json:"CharEnc,omitempty"`
+	FontName string `json:"FontName,omitempty"`
+}
+
+// A SubtitleConfig is the subtitle settings of the output.
+type SubtitleConfig struct {
+	ExtSubtitleList []ExtSubtitle `json:"ExtSubtitleList,omitempty"`
+}
+
+// An ExtXStreamInfo is the EXT-X-STREAM-INF of a variant
+// in the master playlist.
+type ExtXStreamInfo struct {
+	BandWidth string `json:"BandWidth,omitempty"`
+	Audio     string `json:"Audio,omitempty"`
+	Subtitles string `json:"Subtitles,omitempty"`
+}
+
+// A MultiBitrateVideoStream makes the output a video
+// variant of a multi-rate package (HLS or DASH), referring
+// to the transcoding activity which produces it.
+type MultiBitrateVideoStream struct {
+	URI             string          `json:"URI"`
+	RefActivityName string          `json:"RefActivityName,omitempty"`
+	ExtXStreamInfo  *ExtXStreamInfo `json:"ExtXStreamInfo,omitempty"`
+}
+
+// An ExtXMedia makes the output an alternative rendition
+// (EXT-X-MEDIA) of a multi-rate package, e.g., an audio
+// track in another language.
+type ExtXMedia struct {
+	Name     string `json:"Name"`
+	Language string `json:"Language,omitempty"`
+	URI      string `json:"URI"`
+}
+
+// Validate checks the encryption on the client side.
+func (e *Encryption) Validate() error {
+	if e.Type != EncryptionHLSAES128 {
+		return fmt.Errorf("mts: encryption type %q, want %s", e.Type, EncryptionHLSAES128)
+	}
+	if e.Key == "" || e.KeyURI == "" {
+		return fmt.Errorf("mts: encryption wants both Key & KeyUri")
+	}
+	switch e.KeyType {
+	case KeyTypeBase64:
+		key, err := base64.StdEncoding.DecodeString(e.Key)
+		if err != nil {
+			return fmt.Errorf("mts: encryption key: %w", err)
+		}
+		if len(key) != 16 {
+			return fmt.Errorf("mts: encryption key of %d bytes, want 16", len(key))
+		}
+	case KeyTypeKMS:
+	default:
+		return fmt.Errorf("mts: encryption key type %q, want %s or %s", e.KeyType, KeyTypeBase64, KeyTypeKMS)
+	}
+	return nil
+}
+
+// Validate checks the segment on the client side.
+func (s *Segment) Validate() error {
+	if s.Duration == "" {
+		return nil // default to 10s
+	}
+	d, err := strconv.Atoi(s.Duration)
+	if err != nil || d < MinSegmentDuration || d > MaxSegmentDuration {
+		return fmt.Errorf("mts: segment duration %q, want %d to %d seconds",
+			s.Duration, MinSegmentDuration, MaxSegmentDuration)
+	}
+	return nil
+}
+
+// validateHLS checks the HLS & packaging settings of the output.
+func (o *Output) validateHLS() error {
+	var format string
+	if o.Container != nil {
+		format = o.Container.Format
+	}
+	hls := format == "" || format == FormatM3U8 // the template may be m3u8
+
+	if o.Encryption != nil {
+		if !hls {
+			return fmt.Errorf("mts: output %s: encryption wants %s, got %s", o.OutputObject, FormatM3U8, format)
+		}
+		if err := o.Encryption.Validate(); err != nil {
+			return err
+		}
+	}
+	if o.MuxConfig != nil && o.MuxConfig.Segment != nil {
+		if !hls {
+			return fmt.Errorf("mts: output %s: segment wants %s, got %s", o.OutputObject, FormatM3U8, format)
+		}
+		if err := o.MuxConfig.Segment.Validate(); err != nil {
+			return err
+		}
+	}
+	if o.M3U8NonStandardSupport != nil && !hls {
+		return fmt.Errorf("mts: output %s: M3U8NonStandardSupport wants %s, got %s", o.OutputObject, FormatM3U8, format)
+	}
+
+	for _, s := range o.OutSubtitles {
+		if s.Map == "" {
+			return fmt.Errorf("mts: output %s: out subtitle has no Map", o.OutputObject)
+		}
+		if err := checkObject(s.OutSubtitleFile.Object); err != nil {
+			return err
+		}
+	}
+	if o.SubtitleConfig != nil {
+		for _, s := range o.SubtitleConfig.ExtSubtitleList {
+			if err := checkObject(s.Input.Object); err != nil {
+				return err
+			}
+		}
+	}
+
+	if o.MultiBitrateVideoStream != nil && o.ExtXMedia != nil {
+		return fmt.Errorf("mts: output %s: both MultiBitrateVideoStream & ExtXMedia", o.OutputObject)
+	}
+	if s := o.MultiBitrateVideoStream; s != nil && s.URI == "" {
+		return fmt.Errorf("mts: output %s: MultiBitrateVideoStream has no URI", o.OutputObject)
+	}
+	if m := o.ExtXMedia; m != nil && (m.Name == "" || m.URI == "") {
+		return fmt.Errorf("mts: output %s: ExtXMedia wants both Name & URI", o.OutputObject)
+	}
+	return nil
+}
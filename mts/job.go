@@ -2,8 +2,8 @@ package mts
 
 import (
 	"encoding/json"
-	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,7 +31,9 @@ func (a *api) Param() url.Values {
 
 // A SubmitJobsRequest contains the param for submitting a
 // transcoding job. Only the OutputLocation is optional
-// and default to "oss-cn-hangzhou".
+// and default to "oss-cn-hangzhou". The Input & Outputs
+// are JSON strings, use SetInput & SetOutputs to build
+// them from the typed ones.
 type SubmitJobsRequest struct {
 	Input          string `json:"Input"`
 	OutputBucket   string `json:"OutputBucket"`
@@ -70,13 +72,16 @@ func QueryJobsAPI(id string, rest ...string) aliyun.API {
 
 	// api-specific mandotory params
 	a.v.Add("Action", "QueryJobList")
-	ids := append(rest, id)
-	a.v.Add("JobIds", strings.Join(ids, ","))
+	a.v.Add("JobIds", joinIDs(id, rest))
 
-	// fmt.Println(a.v.Encode())
 	return a
 }
 
+// joinIDs joins the ids by comma without touching rest.
+func joinIDs(id string, rest []string) string {
+	return strings.Join(append([]string{id}, rest...), ",")
+}
+
 // JobIO is the job input/output.
 type JobIO struct {
 	Bucket   string `json:"Bucket"`
@@ -85,7 +90,8 @@ type JobIO struct {
 }
 
 // A JobInfo represents the info for one job.
-// The Output has too many fields so it's marshalled to raw bytes.
+// The Output has too many fields so it's marshalled to raw bytes;
+// use OutputInfo to decode it.
 type JobInfo struct {
 	JobID        string          `json:"JobId"`
 	Input        JobIO           `json:"Input"`
@@ -99,15 +105,40 @@ type JobInfo struct {
 	FinishTime   time.Time       `json:"FinishTime"`
 }
 
+// OutputInfo decodes the Output on demand.
+func (j *JobInfo) OutputInfo() (out JobOutputInfo, err error) {
+	if len(j.Output) == 0 {
+		return
+	}
+	err = json.Unmarshal(j.Output, &out)
+	return
+}
+
 // JobOutputInfo is a mapping for JobInfo.Output.
-// This is NOT mean to be completed.
 type JobOutputInfo struct {
-	OutputFile JobIO           `json:"OutputFile"`
-	UserData   string          `json:"UserData"`
-	Priority   string          `json:"Priority"`
-	Properties json.RawMessage `json:"Properties"`
-	ExtendData string          `json:"ExtendData"`
-	TemplateID string          `json:"TemplateId"`
+	OutputFile JobIO      `json:"OutputFile"`
+	TemplateID string     `json:"TemplateId"`
+	UserData   string     `json:"UserData"`
+	Priority   string     `json:"Priority"`
+	ExtendData string     `json:"ExtendData"`
+	Rotate     string     `json:"Rotate"`
+	Properties Properties `json:"Properties"`
+	// settings
+	Container     Container   `json:"Container"`
+	Video         Video       `json:"Video"`
+	Audio         Audio       `json:"Audio"`
+	TransConfig   TransConfig `json:"TransConfig"`
+	MuxConfig     MuxConfig   `json:"MuxConfig"`
+	Clip          Clip        `json:"Clip"`
+	WaterMarkList struct {
+		WaterMark []WaterMark `json:"WaterMark"`
+	} `json:"WaterMarkList"`
+	MergeList struct {
+		Merge []Merge `json:"Merge"`
+	} `json:"MergeList"`
+	// HLS
+	Encryption             Encryption             `json:"Encryption"`
+	M3U8NonStandardSupport M3U8NonStandardSupport `json:"M3U8NonStandardSupport"`
 }
 
 // A JobResult gives the result of a job.
@@ -137,46 +168,144 @@ type QueryJobsResponse struct {
 	} `json:"JobList"`
 }
 
-// A Submitter submits a transcoding job.
-type Submitter interface {
-	Submit(*SubmitJobsRequest) (SubmitJobsResponse, error)
+// CancelJobAPI returns a API for CancelJob.
+// Only jobs not yet started can be cancelled.
+func CancelJobAPI(id string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "CancelJob")
+	a.v.Add("JobId", id)
+
+	return a
 }
 
-// A Querier queries a transcoding job.
-type Querier interface {
-	Query(id string, rest ...string) (QueryJobsResponse, error)
+// A CancelJobResponse contains the response for CancelJob.
+type CancelJobResponse struct {
+	RequestID string `json:"RequestId"`
+	JobID     string `json:"JobId"`
 }
 
-// A Transcoder wraps a Submitter & a Querier.
-type Transcoder interface {
-	Submitter
-	Querier
+// A ListJobRequest contains the param for ListJob.
+// All fields are optional.
+type ListJobRequest struct {
+	// State is one of the job states or "All" (default).
+	State      string
+	PipelineID string
+	// the range of the job creation time
+	Start, End time.Time
+	// PageSize is 1 to 100, default to 10.
+	PageSize      int
+	NextPageToken string
 }
 
-type transcoder struct {
-	signer aliyun.Signer
-	host   string
-	cl     *http.Client
+// fillListJob fills the optional params of ListJob
+// other than the PipelineId.
+func fillListJob(v url.Values, r *ListJobRequest) {
+	if r.State != "" {
+		v.Add("State", r.State)
+	}
+	if !r.Start.IsZero() {
+		v.Add("StartOfJobCreatedTimeRange", aliyun.FormatT(r.Start))
+	}
+	if !r.End.IsZero() {
+		v.Add("EndOfJobCreatedTimeRange", aliyun.FormatT(r.End))
+	}
+	if r.PageSize > 0 {
+		v.Add("MaximumPageSize", strconv.Itoa(r.PageSize))
+	}
+	if r.NextPageToken != "" {
+		v.Add("NextPageToken", r.NextPageToken)
+	}
 }
 
-func (s *transcoder) Submit(r *SubmitJobsRequest) (resp SubmitJobsResponse, err error) {
-	api := SubmitJobsAPI(r)
-	err = aliyun.Get(s.cl, s.signer, api, s.host, &resp)
-	return
+// ListJobAPI returns a API for ListJob.
+func ListJobAPI(r *ListJobRequest) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "ListJob")
+
+	// optional
+	if r.PipelineID != "" {
+		a.v.Add("PipelineId", r.PipelineID)
+	}
+	fillListJob(a.v, r)
+
+	return a
 }
 
-func (s *transcoder) Query(id string, rest ...string) (resp QueryJobsResponse, err error) {
-	api := QueryJobsAPI(id, rest...)
-	err = aliyun.Get(s.cl, s.signer, api, s.host, &resp)
-	return
+// QueryJobListByPipelineAPI returns a API for QueryJobListByPid,
+// which lists the jobs of a pipeline like ListJob, with the
+// PipelineID of the request mandatory.
+func QueryJobListByPipelineAPI(r *ListJobRequest) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "QueryJobListByPid")
+	a.v.Add("PipelineId", r.PipelineID)
+
+	// optional
+	fillListJob(a.v, r)
+
+	return a
+}
+
+// A SearchJobRequest contains the param for SearchJob,
+// which pages by numbers instead of tokens.
+// All fields are optional.
+type SearchJobRequest struct {
+	State      string
+	PipelineID string
+	// the range of the job creation time
+	Start, End time.Time
+	// Page starts from 1.
+	Page     int
+	PageSize int
 }
 
-// New returns a new Transcoder with a 10s-timeout
-// HTTP client.
-func New(s aliyun.Signer, host string) Transcoder {
-	return &transcoder{
-		signer: s,
-		host:   host,
-		cl:     aliyun.TimeoutClient(10 * time.Second),
+// SearchJobAPI returns a API for SearchJob.
+func SearchJobAPI(r *SearchJobRequest) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "SearchJob")
+
+	// optional
+	if r.State != "" {
+		a.v.Add("State", r.State)
+	}
+	if r.PipelineID != "" {
+		a.v.Add("PipelineId", r.PipelineID)
+	}
+	if !r.Start.IsZero() {
+		a.v.Add("StartOfJobCreatedTimeRange", aliyun.FormatT(r.Start))
 	}
+	if !r.End.IsZero() {
+		a.v.Add("EndOfJobCreatedTimeRange", aliyun.FormatT(r.End))
+	}
+	if r.Page > 0 {
+		a.v.Add("PageNumber", strconv.Itoa(r.Page))
+	}
+	if r.PageSize > 0 {
+		a.v.Add("PageSize", strconv.Itoa(r.PageSize))
+	}
+
+	return a
+}
+
+// A ListJobResponse contains the response for ListJob.
+type ListJobResponse struct {
+	RequestID     string `json:"RequestId"`
+	NextPageToken string `json:"NextPageToken"`
+	JobList       struct {
+		Job []JobInfo `json:"Job"`
+	} `json:"JobList"`
+}
+
+// A SearchJobResponse contains the response for SearchJob.
+type SearchJobResponse struct {
+	RequestID  string `json:"RequestId"`
+	TotalCount int64  `json:"TotalCount"`
+	PageNumber int    `json:"PageNumber"`
+	PageSize   int    `json:"PageSize"`
+	JobList    struct {
+		Job []JobInfo `json:"Job"`
+	} `json:"JobList"`
 }
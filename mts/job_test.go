@@ -2,6 +2,8 @@ package mts_test
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"testing"
 
@@ -69,3 +71,130 @@ func TestQuery(t *testing.T) {
 		t.Log(string(bs))
 	}
 }
+
+func TestOutputInfo(t *testing.T) {
+	var j mts.JobInfo
+	err := json.Unmarshal([]byte(`{
+		"JobId": "31fa3c9ca8134f9cec2b4b0b0f787830",
+		"State": "TranscodeSuccess",
+		"Output": {
+			"OutputFile": {"Bucket": "example-bucket", "Location": "oss-cn-hangzhou", "Object": "example-output.mp4"},
+			"TemplateId": "S00000001-200010",
+			"Container": {"Format": "mp4"},
+			"Video": {"Codec": "H.264", "Bitrate": "800", "Width": "1280"},
+			"WaterMarkList": {"WaterMark": [{"WaterMarkTemplateId": "88c6ca184c0e47098a5b665e2a126797"}]},
+			"Properties": {
+				"Width": "1280",
+				"Height": "720",
+				"Duration": "17.56",
+				"Streams": {
+					"VideoStreamList": {"VideoStream": [{"Index": "0", "CodecName": "h264", "Fps": "25"}]},
+					"AudioStreamList": {"AudioStream": [{"Index": "1", "CodecName": "aac", "Samplerate": "44100"}]}
+				},
+				"Format": {"FormatName": "mov,mp4,m4a,3gp,3g2,mj2", "NumStreams": "2"}
+			}
+		}
+	}`), &j)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := j.OutputInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.OutputFile.Object != "example-output.mp4" || out.Container.Format != "mp4" || out.Video.Width != "1280" {
+		t.Errorf("unexpected output %+v", out)
+	}
+	if len(out.WaterMarkList.WaterMark) != 1 {
+		t.Errorf("unexpected watermarks %+v", out.WaterMarkList)
+	}
+	p := out.Properties
+	if p.Height != "720" || p.Format.NumStreams != "2" ||
+		p.Streams.VideoStreamList.VideoStream[0].Fps != "25" ||
+		p.Streams.AudioStreamList.AudioStream[0].Samplerate != "44100" {
+		t.Errorf("unexpected properties %+v", p)
+	}
+}
+
+func TestJobErr(t *testing.T) {
+	j := mts.JobInfo{JobID: "job", State: mts.StateTranscoding}
+	if mts.IsTerminal(j.State) || j.Err() != nil {
+		t.Errorf("%s should not be terminal nor error", j.State)
+	}
+
+	j.State, j.Code, j.Message = mts.StateTranscodeFail, "InvalidParameter.ResourceNotFound", "input not found"
+	if !mts.IsTerminal(j.State) || mts.IsSuccess(j.State) {
+		t.Errorf("%s should be terminal but not success", j.State)
+	}
+	var je *mts.JobError
+	if err := j.Err(); !errors.As(err, &je) || je.Code != j.Code {
+		t.Errorf("want JobError with code %s, got %v", j.Code, err)
+	}
+
+	r := mts.JobResult{Success: false, Code: "QuotaExceeded.Pipeline"}
+	if err := r.Err(); !errors.As(err, &je) || je.Code != r.Code {
+		t.Errorf("want JobError with code %s, got %v", r.Code, err)
+	}
+}
+
+// fakeLister returns a job per page for 3 pages.
+type fakeLister struct {
+	mts.JobManager
+	tokens []string
+}
+
+func (l *fakeLister) List(r *mts.ListJobRequest) (resp mts.ListJobResponse, err error) {
+	l.tokens = append(l.tokens, r.NextPageToken)
+	page := len(l.tokens)
+	resp.JobList.Job = []mts.JobInfo{{JobID: fmt.Sprint("job-", page), PipelineID: r.PipelineID}}
+	if page < 3 {
+		resp.NextPageToken = fmt.Sprint("token-", page)
+	}
+	return
+}
+
+func TestEachJob(t *testing.T) {
+	l := &fakeLister{}
+	var ids []string
+	err := mts.EachJob(l, mts.ListJobRequest{PipelineID: "p"}, func(j mts.JobInfo) error {
+		ids = append(ids, j.JobID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[job-1 job-2 job-3]" || fmt.Sprint(l.tokens) != "[ token-1 token-2]" {
+		t.Errorf("unexpected ids %v with tokens %v", ids, l.tokens)
+	}
+}
+
+func TestQueryJobsAPI(t *testing.T) {
+	ids := []string{"a", "b", "c"}
+	p := mts.QueryJobsAPI(ids[0], ids[1:2]...).Param()
+	if p.Get("JobIds") != "a,b" {
+		t.Errorf("unexpected JobIds %s", p.Get("JobIds"))
+	}
+	if ids[2] != "c" {
+		t.Errorf("ids modified: %v", ids)
+	}
+}
+
+func TestListJobAPIs(t *testing.T) {
+	r := &mts.ListJobRequest{PipelineID: "p", State: mts.StateTranscodeFail, PageSize: 20, NextPageToken: "token"}
+	for action, api := range map[string]aliyun.API{
+		"ListJob":           mts.ListJobAPI(r),
+		"QueryJobListByPid": mts.QueryJobListByPipelineAPI(r),
+	} {
+		p := api.Param()
+		if p.Get("Action") != action || p.Get("PipelineId") != "p" || p.Get("State") != mts.StateTranscodeFail ||
+			p.Get("MaximumPageSize") != "20" || p.Get("NextPageToken") != "token" {
+			t.Errorf("unexpected param %v", p)
+		}
+	}
+
+	p := mts.SearchJobAPI(&mts.SearchJobRequest{Page: 2, PageSize: 50}).Param()
+	if p.Get("Action") != "SearchJob" || p.Get("PageNumber") != "2" || p.Get("PageSize") != "50" || p.Get("PipelineId") != "" {
+		t.Errorf("unexpected param %v", p)
+	}
+}
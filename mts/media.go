@@ -0,0 +1,101 @@
+package mts
+
+// A NetworkCost is the network cost of a video stream.
+type NetworkCost struct {
+	PreloadTime   string `json:"PreloadTime"`
+	CostBandwidth string `json:"CostBandwidth"`
+	AvgBitrate    string `json:"AvgBitrate"`
+}
+
+// A VideoStream is the properties of a video stream.
+// Like the official APIs, all values are strings.
+type VideoStream struct {
+	Index          string      `json:"Index"`
+	CodecName      string      `json:"CodecName"`
+	CodecLongName  string      `json:"CodecLongName"`
+	Profile        string      `json:"Profile"`
+	CodecTimeBase  string      `json:"CodecTimeBase"`
+	CodecTagString string      `json:"CodecTagString"`
+	CodecTag       string      `json:"CodecTag"`
+	Width          string      `json:"Width"`
+	Height         string      `json:"Height"`
+	HasBFrames     string      `json:"HasBFrames"`
+	Sar            string      `json:"Sar"`
+	Dar            string      `json:"Dar"`
+	PixFmt         string      `json:"PixFmt"`
+	Level          string      `json:"Level"`
+	Fps            string      `json:"Fps"`
+	AvgFPS         string      `json:"AvgFPS"`
+	Timebase       string      `json:"Timebase"`
+	StartTime      string      `json:"StartTime"`
+	Duration       string      `json:"Duration"`
+	Bitrate        string      `json:"Bitrate"`
+	NumFrames      string      `json:"NumFrames"`
+	Lang           string      `json:"Lang"`
+	Rotate         string      `json:"Rotate"`
+	NetworkCost    NetworkCost `json:"NetworkCost"`
+}
+
+// An AudioStream is the properties of an audio stream.
+type AudioStream struct {
+	Index          string `json:"Index"`
+	CodecName      string `json:"CodecName"`
+	CodecLongName  string `json:"CodecLongName"`
+	CodecTimeBase  string `json:"CodecTimeBase"`
+	CodecTagString string `json:"CodecTagString"`
+	CodecTag       string `json:"CodecTag"`
+	SampleFmt      string `json:"SampleFmt"`
+	Samplerate     string `json:"Samplerate"`
+	Channels       string `json:"Channels"`
+	ChannelLayout  string `json:"ChannelLayout"`
+	Timebase       string `json:"Timebase"`
+	StartTime      string `json:"StartTime"`
+	Duration       string `json:"Duration"`
+	Bitrate        string `json:"Bitrate"`
+	NumFrames      string `json:"NumFrames"`
+	Lang           string `json:"Lang"`
+}
+
+// A SubtitleStream is the properties of a subtitle stream.
+type SubtitleStream struct {
+	Index string `json:"Index"`
+	Lang  string `json:"Lang"`
+}
+
+// Streams are the streams of a media.
+type Streams struct {
+	VideoStreamList struct {
+		VideoStream []VideoStream `json:"VideoStream"`
+	} `json:"VideoStreamList"`
+	AudioStreamList struct {
+		AudioStream []AudioStream `json:"AudioStream"`
+	} `json:"AudioStreamList"`
+	SubtitleStreamList struct {
+		SubtitleStream []SubtitleStream `json:"SubtitleStream"`
+	} `json:"SubtitleStreamList"`
+}
+
+// A Format is the format (container) info of a media.
+type Format struct {
+	NumStreams     string `json:"NumStreams"`
+	NumPrograms    string `json:"NumPrograms"`
+	FormatName     string `json:"FormatName"`
+	FormatLongName string `json:"FormatLongName"`
+	StartTime      string `json:"StartTime"`
+	Duration       string `json:"Duration"`
+	Size           string `json:"Size"`
+	Bitrate        string `json:"Bitrate"`
+}
+
+// Properties are the properties of a media file.
+type Properties struct {
+	Width      string  `json:"Width"`
+	Height     string  `json:"Height"`
+	Bitrate    string  `json:"Bitrate"`
+	Duration   string  `json:"Duration"`
+	Fps        string  `json:"Fps"`
+	FileSize   string  `json:"FileSize"`
+	FileFormat string  `json:"FileFormat"`
+	Streams    Streams `json:"Streams"`
+	Format     Format  `json:"Format"`
+}
@@ -0,0 +1,157 @@
+package mts
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/practigo/aliyun"
+)
+
+// Media info job states.
+const (
+	MediaInfoSubmitted = "Submitted"
+	MediaInfoAnalyzing = "Analyzing"
+	MediaInfoSuccess   = "Success"
+	MediaInfoFail      = "Fail"
+)
+
+// IsMediaInfoTerminal checks if the media info job state
+// is either Success or Fail.
+func IsMediaInfoTerminal(state string) bool {
+	return state == MediaInfoSuccess || state == MediaInfoFail
+}
+
+// A SubmitMediaInfoJobRequest contains the param for
+// submitting a media info job. In sync mode (Async false)
+// the Properties are returned in the response directly.
+type SubmitMediaInfoJobRequest struct {
+	Input      JobIO
+	Async      bool
+	UserData   string // optional
+	PipelineID string // optional
+}
+
+// SubmitMediaInfoJobAPI returns a API for SubmitMediaInfoJob.
+func SubmitMediaInfoJobAPI(r *SubmitMediaInfoJobRequest) (aliyun.API, error) {
+	if err := checkObject(r.Input.Object); err != nil {
+		return nil, err
+	}
+
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "SubmitMediaInfoJob")
+	in, _ := json.Marshal(r.Input) // no error for strings
+	a.v.Add("Input", string(in))
+	a.v.Add("Async", strconv.FormatBool(r.Async))
+
+	// optional
+	if r.UserData != "" {
+		a.v.Add("UserData", r.UserData)
+	}
+	if r.PipelineID != "" {
+		a.v.Add("PipelineId", r.PipelineID)
+	}
+
+	return a, nil
+}
+
+// QueryMediaInfoJobsAPI returns a API for QueryMediaInfoJobList.
+// It accepts one or more (at most 10) IDs for query.
+func QueryMediaInfoJobsAPI(id string, rest ...string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "QueryMediaInfoJobList")
+	a.v.Add("MediaInfoJobIds", joinIDs(id, rest))
+
+	return a
+}
+
+// A MediaInfoJob represents the info for one media info job.
+type MediaInfoJob struct {
+	JobID        string     `json:"JobId"`
+	Input        JobIO      `json:"Input"`
+	State        string     `json:"State"`
+	Code         string     `json:"Code"`
+	Message      string     `json:"Message"`
+	UserData     string     `json:"UserData"`
+	PipelineID   string     `json:"PipelineId"`
+	Async        bool       `json:"Async"`
+	CreationTime string     `json:"CreationTime"`
+	Properties   Properties `json:"Properties"`
+}
+
+// Err returns a *JobError if the job failed, or nil otherwise.
+func (j *MediaInfoJob) Err() error {
+	if j.State != MediaInfoFail {
+		return nil
+	}
+	return &JobError{
+		JobID:   j.JobID,
+		State:   j.State,
+		Code:    j.Code,
+		Message: j.Message,
+	}
+}
+
+// A SubmitMediaInfoJobResponse contains the response for
+// SubmitMediaInfoJob.
+type SubmitMediaInfoJobResponse struct {
+	RequestID    string       `json:"RequestId"`
+	MediaInfoJob MediaInfoJob `json:"MediaInfoJob"`
+}
+
+// A QueryMediaInfoJobsResponse contains the response for
+// QueryMediaInfoJobList.
+type QueryMediaInfoJobsResponse struct {
+	RequestID               string `json:"RequestId"`
+	NonExistMediaInfoJobIDs struct {
+		IDs []string `json:"String,omitempty"`
+	} `json:"NonExistMediaInfoJobIds"`
+	MediaInfoJobList struct {
+		MediaInfoJob []MediaInfoJob `json:"MediaInfoJob"`
+	} `json:"MediaInfoJobList"`
+}
+
+// Probe gets the properties of the input with a sync
+// media info job, e.g., to choose the templates.
+func Probe(m MediaInfoJobManager, in JobIO) (Properties, error) {
+	resp, err := m.SubmitMediaInfoJob(&SubmitMediaInfoJobRequest{Input: in})
+	if err != nil {
+		return Properties{}, err
+	}
+	return resp.MediaInfoJob.Properties, resp.MediaInfoJob.Err()
+}
+
+// WaitMediaInfo is like Wait but for the async media info
+// jobs, and the Progress is not called.
+func (w *Waiter) WaitMediaInfo(ctx context.Context, q MediaInfoJobManager, ids ...string) ([]MediaInfoJob, error) {
+	res, err := w.waitJobs(ctx, ids, func(batch []string) ([]string, []polledJob, error) {
+		resp, err := q.QueryMediaInfoJobs(batch[0], batch[1:]...)
+		if err != nil {
+			return nil, nil, err
+		}
+		polled := make([]polledJob, len(resp.MediaInfoJobList.MediaInfoJob))
+		for i, j := range resp.MediaInfoJobList.MediaInfoJob {
+			polled[i] = polledJob{j.JobID, IsMediaInfoTerminal(j.State), j}
+		}
+		return resp.NonExistMediaInfoJobIDs.IDs, polled, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]MediaInfoJob, len(res))
+	for i, j := range res {
+		jobs[i] = j.(MediaInfoJob)
+	}
+	return jobs, nil
+}
+
+// WaitMediaInfo waits for the media info jobs using a
+// Waiter with the default intervals.
+func WaitMediaInfo(ctx context.Context, q MediaInfoJobManager, ids ...string) ([]MediaInfoJob, error) {
+	w := &Waiter{}
+	return w.WaitMediaInfo(ctx, q, ids...)
+}
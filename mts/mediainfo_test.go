@@ -0,0 +1,82 @@
+package mts_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/practigo/aliyun/mts"
+)
+
+func TestSubmitMediaInfoJobAPI(t *testing.T) {
+	in := mts.JobIO{Bucket: "b", Location: "oss-cn-hangzhou", Object: "example.mp4"}
+	api, err := mts.SubmitMediaInfoJobAPI(&mts.SubmitMediaInfoJobRequest{Input: in, Async: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := api.Param()
+	if p.Get("Async") != "true" || p.Get("Input") != `{"Bucket":"b","Location":"oss-cn-hangzhou","Object":"example.mp4"}` {
+		t.Errorf("unexpected param %v", p)
+	}
+
+	in.Object = "not escaped.mp4"
+	if _, err = mts.SubmitMediaInfoJobAPI(&mts.SubmitMediaInfoJobRequest{Input: in}); err == nil {
+		t.Error("unescaped input should be invalid")
+	}
+}
+
+// fakeMediaInfo probes the "bad.mp4" as failed, and moves
+// the async jobs through the stages.
+type fakeMediaInfo struct {
+	stages
+}
+
+func (f *fakeMediaInfo) SubmitMediaInfoJob(r *mts.SubmitMediaInfoJobRequest) (resp mts.SubmitMediaInfoJobResponse, err error) {
+	j := &resp.MediaInfoJob
+	j.JobID, j.Input, j.State = "probe", r.Input, mts.MediaInfoSuccess
+	if r.Input.Object == "bad.mp4" {
+		j.State, j.Code, j.Message = mts.MediaInfoFail, "InvalidInput.NotSupported", "unsupported"
+		return
+	}
+	j.Properties.Width, j.Properties.Height = "1280", "720"
+	return
+}
+
+func (f *fakeMediaInfo) QueryMediaInfoJobs(id string, rest ...string) (resp mts.QueryMediaInfoJobsResponse, err error) {
+	state := f.next(mts.MediaInfoSubmitted, mts.MediaInfoAnalyzing, mts.MediaInfoSuccess)
+	for _, id := range append([]string{id}, rest...) {
+		resp.MediaInfoJobList.MediaInfoJob = append(resp.MediaInfoJobList.MediaInfoJob,
+			mts.MediaInfoJob{JobID: id, State: state})
+	}
+	return
+}
+
+func TestProbe(t *testing.T) {
+	f := &fakeMediaInfo{}
+	props, err := mts.Probe(f, mts.JobIO{Object: "example.mp4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if props.Width != "1280" || props.Height != "720" {
+		t.Errorf("unexpected properties %+v", props)
+	}
+
+	_, err = mts.Probe(f, mts.JobIO{Object: "bad.mp4"})
+	var je *mts.JobError
+	if !errors.As(err, &je) || je.JobID != "probe" || je.Code != "InvalidInput.NotSupported" {
+		t.Errorf("want a *JobError, got %v", err)
+	}
+}
+
+func TestWaitMediaInfo(t *testing.T) {
+	f := &fakeMediaInfo{}
+	w := &mts.Waiter{Interval: time.Millisecond}
+	jobs, err := w.WaitMediaInfo(context.Background(), f, "a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[1].JobID != "b" || jobs[1].State != mts.MediaInfoSuccess || f.queried != 3 {
+		t.Errorf("unexpected jobs %+v after %d queries", jobs, f.queried)
+	}
+}
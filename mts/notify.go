@@ -0,0 +1,118 @@
+package mts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/practigo/aliyun/mns"
+)
+
+// A Notification is the message MTS sends to the MNS queue
+// (or topic) in the NotifyConfig of the pipeline when a job
+// completes. The keys are matched case-insensitively, as
+// MTS sends both "JobId" and "jobId".
+type Notification struct {
+	JobID     string `json:"JobId"`
+	RequestID string `json:"RequestId"`
+	RunID     string `json:"RunId"`
+	// Type is the job type, e.g., Transcode or Snapshot.
+	Type     string `json:"Type"`
+	State    string `json:"State"`
+	UserData string `json:"UserData"`
+	Code     string `json:"Code"`
+	Message  string `json:"Message"`
+	// Output is present for some job types; use
+	// OutputInfo to decode it.
+	Output json.RawMessage `json:"Output"`
+}
+
+// OutputInfo decodes the Output on demand.
+func (n *Notification) OutputInfo() (out JobOutputInfo, err error) {
+	if len(n.Output) == 0 {
+		return
+	}
+	err = json.Unmarshal(n.Output, &out)
+	return
+}
+
+// DecodeNotification decodes the message body, either
+// plain JSON or base64 encoded JSON.
+func DecodeNotification(body []byte) (n Notification, err error) {
+	body = bytes.TrimSpace(body)
+	if !bytes.HasPrefix(body, []byte("{")) {
+		if body, err = mns.DecodeFromBase64(body); err != nil {
+			return
+		}
+	}
+	err = json.Unmarshal(body, &n)
+	return
+}
+
+// A Receiver receives & deletes the messages from a MNS
+// queue; it is implemented by *mns.Messager.
+type Receiver interface {
+	Receive(queue string, waitSeconds int) (mns.ReceiveMessageResponse, error)
+	Delete(queue, receipt string) error
+}
+
+// A Consumer consumes the notifications from a MNS queue,
+// replacing polling with Query.
+type Consumer struct {
+	Receiver Receiver
+	Queue    string
+	// Handler is called for each notification; the message
+	// is deleted only if it returns nil, otherwise it will be
+	// received again after the visibility timeout. Messages
+	// failed to decode are deleted after OnError.
+	Handler func(Notification) error
+	// OnError, if not nil, is called with the errors of
+	// receiving, decoding, handling or deleting.
+	OnError func(error)
+}
+
+func (c *Consumer) error(err error) {
+	if c.OnError != nil {
+		c.OnError(err)
+	}
+}
+
+// Run receives the messages with long polling until the
+// ctx is done. Note that a receive in progress is not
+// interrupted.
+func (c *Consumer) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		msg, err := c.Receiver.Receive(c.Queue, mns.MaxWaitSeconds)
+		if err != nil {
+			if !mns.IsNoMessage(err) {
+				c.error(err)
+				// backoff a little on errors
+				select {
+				case <-ctx.Done():
+				case <-time.After(time.Second):
+				}
+			}
+			continue
+		}
+
+		n, err := DecodeNotification(msg.MessageBody)
+		if err != nil {
+			// never decodable, delete it instead of
+			// receiving it again and again
+			c.error(err)
+		} else if err = c.Handler(n); err != nil {
+			c.error(err)
+			continue
+		}
+		if err = c.Receiver.Delete(c.Queue, msg.ReceiptHandle); err != nil {
+			c.error(err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package mts_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/practigo/aliyun"
+	"github.com/practigo/aliyun/mns"
+	"github.com/practigo/aliyun/mts"
+)
+
+const testNotification = `{"jobId":"2376030d9d0849399cd20e20c876b2e8","requestId":"59B9D4B6-5C8E-4C0B-9D4D-4C7E2E0F0E3A","Type":"Transcode","state":"Success","type":"Transcode","State":"Success","JobId":"2376030d9d0849399cd20e20c876b2e8","UserData":"testid-001"}`
+
+func TestDecodeNotification(t *testing.T) {
+	for _, body := range [][]byte{
+		[]byte(testNotification),
+		mns.Encode2Base64([]byte(testNotification)),
+	} {
+		n, err := mts.DecodeNotification(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n.JobID != "2376030d9d0849399cd20e20c876b2e8" || n.State != "Success" ||
+			n.Type != "Transcode" || n.UserData != "testid-001" {
+			t.Errorf("unexpected notification %+v", n)
+		}
+	}
+}
+
+// fakeQueue serves the messages once and then cancels.
+type fakeQueue struct {
+	msgs    [][]byte
+	deleted []string
+	cancel  func()
+}
+
+func (q *fakeQueue) Receive(queue string, waitSeconds int) (resp mns.ReceiveMessageResponse, err error) {
+	if len(q.msgs) == 0 {
+		q.cancel()
+		return resp, &aliyun.CanonicalizedError{Code: "MessageNotExist"}
+	}
+	resp.MessageBody, resp.ReceiptHandle = q.msgs[0], string(q.msgs[0])
+	q.msgs = q.msgs[1:]
+	return
+}
+
+func (q *fakeQueue) Delete(queue, receipt string) error {
+	q.deleted = append(q.deleted, receipt)
+	return nil
+}
+
+func TestConsumer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &fakeQueue{
+		msgs: [][]byte{
+			mns.Encode2Base64([]byte(testNotification)),
+			[]byte("not a notification"),
+			[]byte(`{"JobId":"fail-to-handle"}`),
+		},
+		cancel: cancel,
+	}
+
+	var handled, errs int
+	c := &mts.Consumer{
+		Receiver: q,
+		Queue:    "mts",
+		Handler: func(n mts.Notification) error {
+			handled++
+			if n.JobID == "fail-to-handle" {
+				return errors.New("handler error")
+			}
+			return nil
+		},
+		OnError: func(error) { errs++ },
+	}
+	if err := c.Run(ctx); err != context.Canceled {
+		t.Errorf("want canceled, got %v", err)
+	}
+	if handled != 2 || errs != 2 {
+		t.Errorf("want 2 handled & 2 errors, got %d & %d", handled, errs)
+	}
+	// the undecodable one is deleted, while the one failed
+	// to handle is kept for redelivery
+	if len(q.deleted) != 2 || q.deleted[1] != "not a notification" {
+		t.Errorf("unexpected deleted %q", q.deleted)
+	}
+}
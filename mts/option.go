@@ -0,0 +1,113 @@
+package mts
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/practigo/aliyun"
+)
+
+// An Option configures the Client returned by New.
+type Option func(*client)
+
+// WithHTTPClient uses the cl instead of the default
+// 10s-timeout client. The cl is not modified by the other
+// options.
+func WithHTTPClient(cl *http.Client) Option {
+	return func(c *client) {
+		c.cl = cl
+	}
+}
+
+// WithRegion sets the RegionId of every call, e.g.,
+// cn-hangzhou, unless already set by the API.
+func WithRegion(region string) Option {
+	return func(c *client) {
+		c.region = region
+	}
+}
+
+// WithPipeline sets the default PipelineId of the submit
+// calls, used if the request leaves it empty.
+func WithPipeline(id string) Option {
+	return func(c *client) {
+		c.pipeline = id
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every call.
+func WithUserAgent(ua string) Option {
+	return WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripper(func(r *http.Request) (*http.Response, error) {
+			r = r.Clone(r.Context()) // a RoundTripper should not modify the request
+			r.Header.Set("User-Agent", ua)
+			return next.RoundTrip(r)
+		})
+	})
+}
+
+// WithMiddleware wraps the transport of the HTTP client,
+// e.g., for logging or metrics. The middlewares apply in
+// the order given, the first being the outermost.
+func WithMiddleware(mw func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *client) {
+		c.mws = append(c.mws, mw)
+	}
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// submitActions take a default PipelineId.
+var submitActions = map[string]bool{
+	"SubmitJobs":         true,
+	"SubmitSnapshotJob":  true,
+	"SubmitMediaInfoJob": true,
+}
+
+// defaultAPI fills the defaults of the client to the
+// param of the API.
+type defaultAPI struct {
+	aliyun.API
+	region   string
+	pipeline string
+}
+
+func (a *defaultAPI) Param() url.Values {
+	v := url.Values{}
+	for k, vs := range a.API.Param() {
+		v[k] = append([]string(nil), vs...)
+	}
+	if a.region != "" && v.Get("RegionId") == "" {
+		v.Set("RegionId", a.region)
+	}
+	if a.pipeline != "" && v.Get("PipelineId") == "" && submitActions[v.Get("Action")] {
+		v.Set("PipelineId", a.pipeline)
+	}
+	return v
+}
+
+// apply applies the options and wraps the transport with
+// the middlewares.
+func (c *client) apply(opts []Option) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	if len(c.mws) == 0 {
+		return
+	}
+
+	cl := *c.cl // shallow copy to keep the given one intact
+	t := cl.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	for i := len(c.mws) - 1; i >= 0; i-- {
+		t = c.mws[i](t)
+	}
+	cl.Transport = t
+	c.cl = &cl
+}
@@ -0,0 +1,70 @@
+package mts_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/practigo/aliyun"
+	"github.com/practigo/aliyun/mts"
+)
+
+func TestOptions(t *testing.T) {
+	var got []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != "test-agent" {
+			t.Errorf("want test-agent, got %s", ua)
+		}
+		got = append(got, r.URL.Query())
+		fmt.Fprint(w, `{"RequestId":"test"}`)
+	}))
+	defer srv.Close()
+
+	cl := &http.Client{}
+	var trips int
+	c := mts.New(aliyun.NewAccessKey("id", "secret"), srv.URL,
+		mts.WithHTTPClient(cl),
+		mts.WithRegion("cn-shanghai"),
+		mts.WithPipeline("default-pipeline"),
+		mts.WithUserAgent("test-agent"),
+		mts.WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				trips++
+				return next.RoundTrip(r)
+			})
+		}),
+	)
+
+	if _, err := c.Submit(&mts.SubmitJobsRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Submit(&mts.SubmitJobsRequest{PipelineID: "given"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Query("job"); err != nil {
+		t.Fatal(err)
+	}
+
+	if trips != 3 || len(got) != 3 {
+		t.Fatalf("want 3 calls, got %d trips & %d requests", trips, len(got))
+	}
+	for i, want := range []string{"default-pipeline", "given", ""} {
+		if p := got[i].Get("PipelineId"); p != want {
+			t.Errorf("call %d: want pipeline %q, got %q", i, want, p)
+		}
+		if r := got[i].Get("RegionId"); r != "cn-shanghai" {
+			t.Errorf("call %d: want region cn-shanghai, got %q", i, r)
+		}
+	}
+	if cl.Transport != nil {
+		t.Error("the given client should not be modified")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,241 @@
+package mts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/practigo/aliyun"
+)
+
+// MaxOutputs is the max number of outputs in a SubmitJobs.
+const MaxOutputs = 30
+
+// A Container overrides the container of the template.
+type Container struct {
+	Format string `json:"Format,omitempty"`
+}
+
+// A Video overrides the video settings of the template.
+// Values are strings as in the official doc, e.g., "1280".
+type Video struct {
+	Codec    string `json:"Codec,omitempty"`
+	Profile  string `json:"Profile,omitempty"`
+	Bitrate  string `json:"Bitrate,omitempty"`
+	Crf      string `json:"Crf,omitempty"`
+	Width    string `json:"Width,omitempty"`
+	Height   string `json:"Height,omitempty"`
+	Fps      string `json:"Fps,omitempty"`
+	MaxFps   string `json:"MaxFps,omitempty"`
+	Gop      string `json:"Gop,omitempty"`
+	Preset   string `json:"Preset,omitempty"`
+	ScanMode string `json:"ScanMode,omitempty"`
+	Bufsize  string `json:"Bufsize,omitempty"`
+	Maxrate  string `json:"Maxrate,omitempty"`
+	PixFmt   string `json:"PixFmt,omitempty"`
+	Crop     string `json:"Crop,omitempty"`
+	Pad      string `json:"Pad,omitempty"`
+	Remove   string `json:"Remove,omitempty"`
+}
+
+// An Audio overrides the audio settings of the template.
+type Audio struct {
+	Codec      string `json:"Codec,omitempty"`
+	Profile    string `json:"Profile,omitempty"`
+	Samplerate string `json:"Samplerate,omitempty"`
+	Bitrate    string `json:"Bitrate,omitempty"`
+	Channels   string `json:"Channels,omitempty"`
+	Qscale     string `json:"Qscale,omitempty"`
+	Remove     string `json:"Remove,omitempty"`
+}
+
+// A TransConfig controls the transcoding behavior.
+type TransConfig struct {
+	TransMode               string `json:"TransMode,omitempty"`
+	AdjDarMethod            string `json:"AdjDarMethod,omitempty"`
+	IsCheckReso             string `json:"IsCheckReso,omitempty"`
+	IsCheckResoFail         string `json:"IsCheckResoFail,omitempty"`
+	IsCheckVideoBitrate     string `json:"IsCheckVideoBitrate,omitempty"`
+	IsCheckVideoBitrateFail string `json:"IsCheckVideoBitrateFail,omitempty"`
+	IsCheckAudioBitrate     string `json:"IsCheckAudioBitrate,omitempty"`
+	IsCheckAudioBitrateFail string `json:"IsCheckAudioBitrateFail,omitempty"`
+}
+
+// A WaterMark adds a watermark to the output, usually
+// by a WaterMarkTemplateID and the image InputFile.
+type WaterMark struct {
+	InputFile           *JobIO `json:"InputFile,omitempty"`
+	WaterMarkTemplateID string `json:"WaterMarkTemplateId,omitempty"`
+	Type                string `json:"Type,omitempty"`
+	ReferPos            string `json:"ReferPos,omitempty"`
+	Dx                  string `json:"Dx,omitempty"`
+	Dy                  string `json:"Dy,omitempty"`
+	Width               string `json:"Width,omitempty"`
+	Height              string `json:"Height,omitempty"`
+}
+
+// A TimeSpan is a period of the input, in the form of
+// seconds (e.g., "12.5") or "hh:mm:ss[.SSS]".
+type TimeSpan struct {
+	Seek     string `json:"Seek,omitempty"`
+	Duration string `json:"Duration,omitempty"`
+}
+
+// A Clip clips the input.
+type Clip struct {
+	TimeSpan TimeSpan `json:"TimeSpan"`
+}
+
+// A Merge is an extra input to be appended.
+type Merge struct {
+	MergeURL string `json:"MergeURL"`
+	Start    string `json:"Start,omitempty"`
+	Duration string `json:"Duration,omitempty"`
+}
+
+// An Encryption encrypts the output, e.g., with
+// Type "hls-aes-128" for HLS.
+type Encryption struct {
+	Type    string `json:"Type"`
+	ID      string `json:"Id,omitempty"`
+	Key     string `json:"Key,omitempty"`
+	KeyURI  string `json:"KeyUri,omitempty"`
+	KeyType string `json:"KeyType,omitempty"`
+	SkipCnt string `json:"SkipCnt,omitempty"`
+}
+
+// A TSSupport adds non-standard info of the TS segments
+// to the M3U8 playlist.
+type TSSupport struct {
+	Md5Support  bool `json:"Md5Support,omitempty"`
+	SizeSupport bool `json:"SizeSupport,omitempty"`
+}
+
+// M3U8NonStandardSupport are the non-standard M3U8 settings.
+type M3U8NonStandardSupport struct {
+	TS *TSSupport `json:"TS,omitempty"`
+}
+
+// An Output is a single output of a SubmitJobs.
+// Only OutputObject & TemplateID are mandatory.
+type Output struct {
+	OutputObject string `json:"OutputObject"`
+	TemplateID   string `json:"TemplateId"`
+	// overrides of the template
+	Container   *Container   `json:"Container,omitempty"`
+	Video       *Video       `json:"Video,omitempty"`
+	Audio       *Audio       `json:"Audio,omitempty"`
+	TransConfig *TransConfig `json:"TransConfig,omitempty"`
+	MuxConfig   *MuxConfig   `json:"MuxConfig,omitempty"`
+	// processing
+	WaterMarks []WaterMark `json:"WaterMarks,omitempty"`
+	Clip       *Clip       `json:"Clip,omitempty"`
+	MergeList  []Merge     `json:"MergeList,omitempty"`
+	// subtitles
+	OutSubtitles   []OutSubtitle   `json:"OutSubtitles,omitempty"`
+	SubtitleConfig *SubtitleConfig `json:"SubtitleConfig,omitempty"`
+	// HLS & multi-rate packaging
+	Encryption              *Encryption              `json:"Encryption,omitempty"`
+	M3U8NonStandardSupport  *M3U8NonStandardSupport  `json:"M3U8NonStandardSupport,omitempty"`
+	MultiBitrateVideoStream *MultiBitrateVideoStream `json:"MultiBitrateVideoStream,omitempty"`
+	ExtXMedia               *ExtXMedia               `json:"ExtXMedia,omitempty"`
+	// misc
+	UserData string `json:"UserData,omitempty"`
+	Priority string `json:"Priority,omitempty"`
+}
+
+// EscapeObject URL-encodes the object key as required
+// by MTS, keeping the '/'.
+func EscapeObject(key string) string {
+	segs := strings.Split(key, "/")
+	for i, s := range segs {
+		segs[i] = url.QueryEscape(s)
+	}
+	return strings.Join(segs, "/")
+}
+
+// FromOSS returns the JobIO for the OSS location, with
+// the Location derived from the Endpoint and the Object
+// escaped by EscapeObject.
+func FromOSS(o aliyun.OSS) JobIO {
+	loc := o.Endpoint
+	if i := strings.Index(loc, "."); i >= 0 {
+		loc = loc[:i]
+	}
+	return JobIO{
+		Bucket:   o.Bucket,
+		Location: strings.TrimSuffix(loc, "-internal"),
+		Object:   EscapeObject(o.Object),
+	}
+}
+
+// checkObject checks if the object key is URL-encoded.
+func checkObject(key string) error {
+	if key == "" {
+		return fmt.Errorf("mts: empty object key")
+	}
+	for _, c := range key {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			strings.ContainsRune("-_.~/+%", c):
+		default:
+			return fmt.Errorf("mts: object key %q is not URL-encoded, see EscapeObject", key)
+		}
+	}
+	if _, err := url.QueryUnescape(key); err != nil {
+		return fmt.Errorf("mts: object key %q: %w", key, err)
+	}
+	return nil
+}
+
+// Validate checks the output on the client side.
+func (o *Output) Validate() error {
+	if err := checkObject(o.OutputObject); err != nil {
+		return err
+	}
+	if o.TemplateID == "" {
+		return fmt.Errorf("mts: output %s has no TemplateId", o.OutputObject)
+	}
+	for _, w := range o.WaterMarks {
+		if w.InputFile != nil {
+			if err := checkObject(w.InputFile.Object); err != nil {
+				return err
+			}
+		}
+	}
+	return o.validateHLS()
+}
+
+// SetInput sets the Input of the request. The Object
+// must be URL-encoded.
+func (r *SubmitJobsRequest) SetInput(in JobIO) error {
+	if err := checkObject(in.Object); err != nil {
+		return err
+	}
+	bs, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	r.Input = string(bs)
+	return nil
+}
+
+// SetOutputs validates the outputs and sets them as the
+// Outputs of the request.
+func (r *SubmitJobsRequest) SetOutputs(outs ...Output) error {
+	if len(outs) == 0 || len(outs) > MaxOutputs {
+		return fmt.Errorf("mts: %d outputs, want 1 to %d", len(outs), MaxOutputs)
+	}
+	for i := range outs {
+		if err := outs[i].Validate(); err != nil {
+			return err
+		}
+	}
+	bs, err := json.Marshal(outs)
+	if err != nil {
+		return err
+	}
+	r.Outputs = string(bs)
+	return nil
+}
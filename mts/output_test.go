@@ -0,0 +1,142 @@
+package mts_test
+
+import (
+	"testing"
+
+	"github.com/practigo/aliyun"
+	"github.com/practigo/aliyun/mts"
+)
+
+func TestSetOutputs(t *testing.T) {
+	var req mts.SubmitJobsRequest
+	err := req.SetInput(mts.JobIO{
+		Bucket:   "example-bucket",
+		Location: "oss-cn-hangzhou",
+		Object:   "example.flv",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Bucket":"example-bucket","Location":"oss-cn-hangzhou","Object":"example.flv"}`; req.Input != want {
+		t.Errorf("want input %s, got %s", want, req.Input)
+	}
+
+	err = req.SetOutputs(mts.Output{
+		OutputObject: "example-output.flv",
+		TemplateID:   "S00000000-000010",
+		WaterMarks: []mts.WaterMark{{
+			InputFile:           &mts.JobIO{Bucket: "example-bucket", Location: "oss-cn-hangzhou", Object: "example-logo.png"},
+			WaterMarkTemplateID: "88c6ca184c0e47098a5b665e2a126797",
+		}},
+		UserData: "testid-001",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the one from the official doc
+	want := `[{"OutputObject":"example-output.flv","TemplateId":"S00000000-000010","WaterMarks":[{"InputFile":{"Bucket":"example-bucket","Location":"oss-cn-hangzhou","Object":"example-logo.png"},"WaterMarkTemplateId":"88c6ca184c0e47098a5b665e2a126797"}],"UserData":"testid-001"}]`
+	if req.Outputs != want {
+		t.Errorf("want outputs %s, got %s", want, req.Outputs)
+	}
+}
+
+func TestValidateOutputs(t *testing.T) {
+	var req mts.SubmitJobsRequest
+	if err := req.SetOutputs(); err == nil {
+		t.Error("should fail with no output")
+	}
+	if err := req.SetOutputs(make([]mts.Output, mts.MaxOutputs+1)...); err == nil {
+		t.Error("should fail with too many outputs")
+	}
+	if err := req.SetOutputs(mts.Output{OutputObject: "a b.mp4", TemplateID: "t"}); err == nil {
+		t.Error("should fail with unescaped object")
+	}
+	key := mts.EscapeObject("dir/中文 name.mp4")
+	if err := req.SetOutputs(mts.Output{OutputObject: key, TemplateID: "t"}); err != nil {
+		t.Errorf("should pass with escaped object %s: %v", key, err)
+	}
+}
+
+func TestNewWaterMark(t *testing.T) {
+	w := mts.NewWaterMark("wid", aliyun.OSS{
+		Bucket:   "example-bucket",
+		Endpoint: "oss-cn-hangzhou-internal.aliyuncs.com",
+		Object:   "logos/中文.png",
+	})
+	want := mts.JobIO{
+		Bucket:   "example-bucket",
+		Location: "oss-cn-hangzhou",
+		Object:   "logos/%E4%B8%AD%E6%96%87.png",
+	}
+	if w.WaterMarkTemplateID != "wid" || *w.InputFile != want {
+		t.Errorf("unexpected watermark %+v", w.InputFile)
+	}
+}
+
+func TestValidateHLS(t *testing.T) {
+	hls := func() mts.Output {
+		return mts.Output{
+			OutputObject: "hls/example.m3u8",
+			TemplateID:   "S00000001-100020",
+			Container:    &mts.Container{Format: mts.FormatM3U8},
+			MuxConfig:    &mts.MuxConfig{Segment: &mts.Segment{Duration: "6"}},
+			Encryption: &mts.Encryption{
+				Type:    mts.EncryptionHLSAES128,
+				KeyType: mts.KeyTypeBase64,
+				Key:     "MDEyMzQ1Njc4OWFiY2RlZg==", // 0123456789abcdef
+				KeyURI:  "https://example.com/key",
+			},
+			MultiBitrateVideoStream: &mts.MultiBitrateVideoStream{
+				URI:            "720p",
+				ExtXStreamInfo: &mts.ExtXStreamInfo{BandWidth: "1500000"},
+			},
+		}
+	}
+	var req mts.SubmitJobsRequest
+	if err := req.SetOutputs(hls()); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, f := range map[string]func(*mts.Output){
+		"mp4":           func(o *mts.Output) { o.Container.Format = "mp4" },
+		"long segment":  func(o *mts.Output) { o.MuxConfig.Segment.Duration = "61" },
+		"short key":     func(o *mts.Output) { o.Encryption.Key = "MDEyMzQ1Njc=" },
+		"no key uri":    func(o *mts.Output) { o.Encryption.KeyURI = "" },
+		"unknown type":  func(o *mts.Output) { o.Encryption.Type = "aes-256" },
+		"both variants": func(o *mts.Output) { o.ExtXMedia = &mts.ExtXMedia{Name: "en", URI: "en"} },
+		"no map":        func(o *mts.Output) { o.OutSubtitles = []mts.OutSubtitle{{}} },
+	} {
+		o := hls()
+		f(&o)
+		if err := req.SetOutputs(o); err == nil {
+			t.Errorf("%s: should be invalid", name)
+		}
+	}
+}
+
+func TestSetOutputsSubtitles(t *testing.T) {
+	var req mts.SubmitJobsRequest
+	err := req.SetOutputs(mts.Output{
+		OutputObject: "example-output.mp4",
+		TemplateID:   "S00000001-200010",
+		OutSubtitles: []mts.OutSubtitle{{
+			Map:             "0:3",
+			OutSubtitleFile: mts.JobIO{Bucket: "example-bucket", Location: "oss-cn-hangzhou", Object: "example-output.vtt"},
+		}},
+		SubtitleConfig: &mts.SubtitleConfig{
+			ExtSubtitleList: []mts.ExtSubtitle{{
+				Input:   mts.JobIO{Bucket: "example-bucket", Location: "oss-cn-hangzhou", Object: "example.srt"},
+				CharEnc: "UTF-8",
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"OutputObject":"example-output.mp4","TemplateId":"S00000001-200010",` +
+		`"OutSubtitles":[{"Map":"0:3","OutSubtitleFile":{"Bucket":"example-bucket","Location":"oss-cn-hangzhou","Object":"example-output.vtt"}}],` +
+		`"SubtitleConfig":{"ExtSubtitleList":[{"Input":{"Bucket":"example-bucket","Location":"oss-cn-hangzhou","Object":"example.srt"},"CharEnc":"UTF-8"}]}}]`
+	if req.Outputs != want {
+		t.Errorf("want outputs %s, got %s", want, req.Outputs)
+	}
+}
@@ -0,0 +1,163 @@
+package mts
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/practigo/aliyun"
+)
+
+// Pipeline states & speeds.
+const (
+	PipelineActive = "Active"
+	PipelinePaused = "Paused"
+
+	SpeedStandard       = "Standard"
+	SpeedBoost          = "Boost"
+	SpeedNarrowBandHDV2 = "NarrowBandHDV2"
+)
+
+// A NotifyConfig sends the job-completion notifications
+// to a MNS queue or topic.
+type NotifyConfig struct {
+	QueueName string `json:"QueueName,omitempty"`
+	Topic     string `json:"Topic,omitempty"`
+}
+
+// A Pipeline is a MTS pipeline (queue of jobs).
+type Pipeline struct {
+	ID           string       `json:"Id"`
+	Name         string       `json:"Name"`
+	State        string       `json:"State"`
+	Speed        string       `json:"Speed"`
+	SpeedLevel   int          `json:"SpeedLevel"`
+	Role         string       `json:"Role"`
+	NotifyConfig NotifyConfig `json:"NotifyConfig"`
+}
+
+// fillPipeline fills the optional params of the pipeline.
+func fillPipeline(v url.Values, p *Pipeline) {
+	if p.Role != "" {
+		v.Add("Role", p.Role)
+	}
+	if p.NotifyConfig != (NotifyConfig{}) {
+		bs, _ := json.Marshal(p.NotifyConfig) // no error for strings
+		v.Add("NotifyConfig", string(bs))
+	}
+}
+
+// AddPipelineAPI returns a API for AddPipeline. The Name
+// is mandotory; the Speed, Role & NotifyConfig are optional.
+func AddPipelineAPI(p *Pipeline) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "AddPipeline")
+	a.v.Add("Name", p.Name)
+
+	// optional
+	if p.Speed != "" {
+		a.v.Add("Speed", p.Speed)
+	}
+	fillPipeline(a.v, p)
+
+	return a
+}
+
+// UpdatePipelineAPI returns a API for UpdatePipeline. The ID,
+// Name & State are mandotory; the Role & NotifyConfig are
+// optional. The Speed is not updatable and thus not sent.
+func UpdatePipelineAPI(p *Pipeline) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "UpdatePipeline")
+	a.v.Add("PipelineId", p.ID)
+	a.v.Add("Name", p.Name)
+	a.v.Add("State", p.State)
+
+	// optional
+	fillPipeline(a.v, p)
+
+	return a
+}
+
+// QueryPipelinesAPI returns a API for QueryPipelineList.
+// It accepts one or more (at most 10) PipelineIDs for query.
+func QueryPipelinesAPI(id string, rest ...string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "QueryPipelineList")
+	a.v.Add("PipelineIds", joinIDs(id, rest))
+
+	return a
+}
+
+// SearchPipelinesAPI returns a API for SearchPipeline.
+// The state is "All" if empty; page starts from 1 and
+// size is 1 to 100.
+func SearchPipelinesAPI(state string, page, size int) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "SearchPipeline")
+
+	// optional
+	if state != "" {
+		a.v.Add("State", state)
+	}
+	if page > 0 {
+		a.v.Add("PageNumber", strconv.Itoa(page))
+	}
+	if size > 0 {
+		a.v.Add("PageSize", strconv.Itoa(size))
+	}
+
+	return a
+}
+
+// DeletePipelineAPI returns a API for DeletePipeline.
+func DeletePipelineAPI(id string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "DeletePipeline")
+	a.v.Add("PipelineId", id)
+
+	return a
+}
+
+// A PipelineResponse contains the response for
+// AddPipeline & UpdatePipeline.
+type PipelineResponse struct {
+	RequestID string   `json:"RequestId"`
+	Pipeline  Pipeline `json:"Pipeline"`
+}
+
+// A QueryPipelinesResponse contains the response for
+// QueryPipelineList.
+type QueryPipelinesResponse struct {
+	RequestID    string `json:"RequestId"`
+	NonExistPids struct {
+		IDs []string `json:"String,omitempty"`
+	} `json:"NonExistPids"`
+	PipelineList struct {
+		Pipeline []Pipeline `json:"Pipeline"`
+	} `json:"PipelineList"`
+}
+
+// A SearchPipelinesResponse contains the response for
+// SearchPipeline.
+type SearchPipelinesResponse struct {
+	RequestID    string `json:"RequestId"`
+	TotalCount   int    `json:"TotalCount"`
+	PageNumber   int    `json:"PageNumber"`
+	PageSize     int    `json:"PageSize"`
+	PipelineList struct {
+		Pipeline []Pipeline `json:"Pipeline"`
+	} `json:"PipelineList"`
+}
+
+// A DeletePipelineResponse contains the response for
+// DeletePipeline.
+type DeletePipelineResponse struct {
+	RequestID  string `json:"RequestId"`
+	PipelineID string `json:"PipelineId"`
+}
@@ -0,0 +1,48 @@
+package mts_test
+
+import (
+	"testing"
+
+	"github.com/practigo/aliyun/mts"
+)
+
+func TestPipelineAPIs(t *testing.T) {
+	pl := &mts.Pipeline{
+		ID:           "pid",
+		Name:         "name",
+		State:        mts.PipelinePaused,
+		Speed:        mts.SpeedBoost,
+		Role:         "AliyunMTSDefaultRole",
+		NotifyConfig: mts.NotifyConfig{QueueName: "queue", Topic: "topic"},
+	}
+	notify := `{"QueueName":"queue","Topic":"topic"}`
+
+	p := mts.AddPipelineAPI(pl).Param()
+	if p.Get("Action") != "AddPipeline" || p.Get("Name") != "name" || p.Get("Speed") != mts.SpeedBoost ||
+		p.Get("Role") != "AliyunMTSDefaultRole" || p.Get("NotifyConfig") != notify {
+		t.Errorf("unexpected AddPipeline param %v", p)
+	}
+	if _, ok := p["PipelineId"]; ok {
+		t.Errorf("unexpected PipelineId in %v", p)
+	}
+
+	p = mts.UpdatePipelineAPI(pl).Param()
+	if p.Get("Action") != "UpdatePipeline" || p.Get("PipelineId") != "pid" || p.Get("Name") != "name" ||
+		p.Get("State") != mts.PipelinePaused || p.Get("Role") != "AliyunMTSDefaultRole" || p.Get("NotifyConfig") != notify {
+		t.Errorf("unexpected UpdatePipeline param %v", p)
+	}
+	if _, ok := p["Speed"]; ok {
+		t.Errorf("unexpected Speed in %v", p)
+	}
+
+	// the optional params are left out if not set
+	p = mts.AddPipelineAPI(&mts.Pipeline{Name: "name", NotifyConfig: mts.NotifyConfig{Topic: "topic"}}).Param()
+	if p.Get("NotifyConfig") != `{"Topic":"topic"}` {
+		t.Errorf("unexpected NotifyConfig %s", p.Get("NotifyConfig"))
+	}
+	for _, k := range []string{"Speed", "Role"} {
+		if _, ok := p[k]; ok {
+			t.Errorf("unexpected %s in %v", k, p)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package mts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/practigo/aliyun"
+)
+
+// Snapshot job states.
+const (
+	SnapshotSubmitted = "Submitted"
+	SnapshotRunning   = "Snapshoting"
+	SnapshotSuccess   = "Success"
+	SnapshotFail      = "Fail"
+)
+
+// IsSnapshotTerminal checks if the snapshot job state is
+// either Success or Fail.
+func IsSnapshotTerminal(state string) bool {
+	return state == SnapshotSuccess || state == SnapshotFail
+}
+
+// Snapshot frame types.
+const (
+	FrameNormal = "normal"
+	FrameIntra  = "intra"
+)
+
+// CountPlaceholder is replaced by the sequence number in
+// the output Object when taking more than one snapshot.
+const CountPlaceholder = "{Count}"
+
+// A TileOut composes the snapshots into sprite sheets.
+type TileOut struct {
+	Lines         string `json:"Lines,omitempty"`
+	Columns       string `json:"Columns,omitempty"`
+	CellWidth     string `json:"CellWidth,omitempty"`
+	CellHeight    string `json:"CellHeight,omitempty"`
+	Margin        string `json:"Margin,omitempty"`
+	Padding       string `json:"Padding,omitempty"`
+	Color         string `json:"Color,omitempty"`
+	IsKeepCellPic string `json:"IsKeepCellPic,omitempty"`
+	CellSelStep   string `json:"CellSelStep,omitempty"`
+}
+
+// A SnapshotConfig configs a snapshot job.
+// Time is the start (in ms); Interval (in s) & Num take
+// more snapshots, which requires the CountPlaceholder in
+// the OutputFile Object.
+type SnapshotConfig struct {
+	OutputFile JobIO  `json:"OutputFile"`
+	Time       string `json:"Time"`
+	Interval   string `json:"Interval,omitempty"`
+	Num        string `json:"Num,omitempty"`
+	Width      string `json:"Width,omitempty"`
+	Height     string `json:"Height,omitempty"`
+	FrameType  string `json:"FrameType,omitempty"`
+	// Format is "vtt" for the WebVTT sequence.
+	Format string `json:"Format,omitempty"`
+	// sprite sheets
+	TileOutputFile *JobIO   `json:"TileOutputFile,omitempty"`
+	TileOut        *TileOut `json:"TileOut,omitempty"`
+}
+
+// Validate checks the config on the client side.
+func (c *SnapshotConfig) Validate() error {
+	if err := checkObject(strings.Replace(c.OutputFile.Object, CountPlaceholder, "", -1)); err != nil {
+		return err
+	}
+	if c.Time == "" {
+		return fmt.Errorf("mts: snapshot has no Time")
+	}
+	if n, _ := strconv.Atoi(c.Num); (n > 1 || c.Interval != "") &&
+		!strings.Contains(c.OutputFile.Object, CountPlaceholder) {
+		return fmt.Errorf("mts: snapshot object %s has no %s for a sequence",
+			c.OutputFile.Object, CountPlaceholder)
+	}
+	if (c.TileOut == nil) != (c.TileOutputFile == nil) {
+		return fmt.Errorf("mts: snapshot TileOut & TileOutputFile must be set together")
+	}
+	if c.TileOutputFile != nil {
+		return checkObject(c.TileOutputFile.Object)
+	}
+	return nil
+}
+
+// A SubmitSnapshotJobRequest contains the param for
+// submitting a snapshot job.
+type SubmitSnapshotJobRequest struct {
+	Input      JobIO
+	Config     SnapshotConfig
+	UserData   string // optional
+	PipelineID string // optional
+}
+
+// SubmitSnapshotJobAPI returns a API for SubmitSnapshotJob.
+// The request is validated before.
+func SubmitSnapshotJobAPI(r *SubmitSnapshotJobRequest) (aliyun.API, error) {
+	if err := checkObject(r.Input.Object); err != nil {
+		return nil, err
+	}
+	if err := r.Config.Validate(); err != nil {
+		return nil, err
+	}
+
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "SubmitSnapshotJob")
+	in, _ := json.Marshal(r.Input) // no error for strings
+	a.v.Add("Input", string(in))
+	cfg, _ := json.Marshal(r.Config)
+	a.v.Add("SnapshotConfig", string(cfg))
+
+	// optional
+	if r.UserData != "" {
+		a.v.Add("UserData", r.UserData)
+	}
+	if r.PipelineID != "" {
+		a.v.Add("PipelineId", r.PipelineID)
+	}
+
+	return a, nil
+}
+
+// QuerySnapshotJobsAPI returns a API for QuerySnapshotJobList.
+// It accepts one or more (at most 10) IDs for query.
+func QuerySnapshotJobsAPI(id string, rest ...string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "QuerySnapshotJobList")
+	a.v.Add("SnapshotJobIds", joinIDs(id, rest))
+
+	return a
+}
+
+// A SnapshotJob represents the info for one snapshot job.
+type SnapshotJob struct {
+	ID             string         `json:"Id"`
+	UserData       string         `json:"UserData"`
+	PipelineID     string         `json:"PipelineId"`
+	State          string         `json:"State"`
+	Code           string         `json:"Code"`
+	Message        string         `json:"Message"`
+	Count          string         `json:"Count"`
+	TileCount      string         `json:"TileCount"`
+	CreationTime   string         `json:"CreationTime"`
+	Input          JobIO          `json:"Input"`
+	SnapshotConfig SnapshotConfig `json:"SnapshotConfig"`
+}
+
+// Err returns a *JobError if the job failed, or nil otherwise.
+func (j *SnapshotJob) Err() error {
+	if j.State != SnapshotFail {
+		return nil
+	}
+	return &JobError{
+		JobID:   j.ID,
+		State:   j.State,
+		Code:    j.Code,
+		Message: j.Message,
+	}
+}
+
+// A SubmitSnapshotJobResponse contains the response for
+// SubmitSnapshotJob.
+type SubmitSnapshotJobResponse struct {
+	RequestID   string      `json:"RequestId"`
+	SnapshotJob SnapshotJob `json:"SnapshotJob"`
+}
+
+// A QuerySnapshotJobsResponse contains the response for
+// QuerySnapshotJobList.
+type QuerySnapshotJobsResponse struct {
+	RequestID              string `json:"RequestId"`
+	NonExistSnapshotJobIDs struct {
+		IDs []string `json:"String,omitempty"`
+	} `json:"NonExistSnapshotJobIds"`
+	SnapshotJobList struct {
+		SnapshotJob []SnapshotJob `json:"SnapshotJob"`
+	} `json:"SnapshotJobList"`
+}
+
+// WaitSnapshots is like Wait but for the snapshot jobs, and
+// the Progress is not called.
+func (w *Waiter) WaitSnapshots(ctx context.Context, q SnapshotJobManager, ids ...string) ([]SnapshotJob, error) {
+	res, err := w.waitJobs(ctx, ids, func(batch []string) ([]string, []polledJob, error) {
+		resp, err := q.QuerySnapshotJobs(batch[0], batch[1:]...)
+		if err != nil {
+			return nil, nil, err
+		}
+		polled := make([]polledJob, len(resp.SnapshotJobList.SnapshotJob))
+		for i, j := range resp.SnapshotJobList.SnapshotJob {
+			polled[i] = polledJob{j.ID, IsSnapshotTerminal(j.State), j}
+		}
+		return resp.NonExistSnapshotJobIDs.IDs, polled, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]SnapshotJob, len(res))
+	for i, j := range res {
+		jobs[i] = j.(SnapshotJob)
+	}
+	return jobs, nil
+}
+
+// WaitSnapshots waits for the snapshot jobs using a Waiter
+// with the default intervals.
+func WaitSnapshots(ctx context.Context, q SnapshotJobManager, ids ...string) ([]SnapshotJob, error) {
+	w := &Waiter{}
+	return w.WaitSnapshots(ctx, q, ids...)
+}
@@ -0,0 +1,54 @@
+package mts_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/practigo/aliyun/mts"
+)
+
+func TestSnapshotConfig(t *testing.T) {
+	out := mts.JobIO{Bucket: "b", Location: "oss-cn-hangzhou", Object: "thumbs/{Count}.jpg"}
+	for _, c := range []struct {
+		cfg mts.SnapshotConfig
+		ok  bool
+	}{
+		{mts.SnapshotConfig{OutputFile: out, Time: "5"}, true},
+		{mts.SnapshotConfig{OutputFile: out, Time: "5", Interval: "10", Num: "10"}, true},
+		{mts.SnapshotConfig{OutputFile: mts.JobIO{Object: "thumb.jpg"}, Time: "5", Num: "10"}, false},
+		{mts.SnapshotConfig{OutputFile: out}, false},
+		{mts.SnapshotConfig{OutputFile: out, Time: "5", TileOut: &mts.TileOut{Lines: "10"}}, false},
+	} {
+		if err := c.cfg.Validate(); (err == nil) != c.ok {
+			t.Errorf("%+v: want ok %v, got %v", c.cfg, c.ok, err)
+		}
+	}
+}
+
+// fakeSnapshots moves the jobs through the stages.
+type fakeSnapshots struct {
+	mts.SnapshotJobManager
+	stages
+}
+
+func (f *fakeSnapshots) QuerySnapshotJobs(id string, rest ...string) (resp mts.QuerySnapshotJobsResponse, err error) {
+	state := f.next(mts.SnapshotSubmitted, mts.SnapshotRunning, mts.SnapshotSuccess)
+	for _, id := range append([]string{id}, rest...) {
+		resp.SnapshotJobList.SnapshotJob = append(resp.SnapshotJobList.SnapshotJob,
+			mts.SnapshotJob{ID: id, State: state})
+	}
+	return
+}
+
+func TestWaitSnapshots(t *testing.T) {
+	f := &fakeSnapshots{}
+	w := &mts.Waiter{Interval: time.Millisecond}
+	jobs, err := w.WaitSnapshots(context.Background(), f, "a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[1].ID != "b" || jobs[1].State != mts.SnapshotSuccess || f.queried != 3 {
+		t.Errorf("unexpected jobs %+v after %d queries", jobs, f.queried)
+	}
+}
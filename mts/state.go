@@ -0,0 +1,68 @@
+package mts
+
+import "fmt"
+
+// Job states.
+const (
+	StateSubmitted          = "Submitted"
+	StateTranscoding        = "Transcoding"
+	StateTranscodeSuccess   = "TranscodeSuccess"
+	StateTranscodeFail      = "TranscodeFail"
+	StateTranscodeCancelled = "TranscodeCancelled"
+)
+
+// IsTerminal checks if the job state will not change anymore.
+func IsTerminal(state string) bool {
+	return state == StateTranscodeSuccess ||
+		state == StateTranscodeFail ||
+		state == StateTranscodeCancelled
+}
+
+// IsSuccess checks if the job state is TranscodeSuccess.
+func IsSuccess(state string) bool {
+	return state == StateTranscodeSuccess
+}
+
+// A JobError is the failure reason of a job, either failed
+// to submit or to transcode. Use errors.As to act on
+// the Code.
+type JobError struct {
+	JobID   string
+	State   string // empty if failed to submit
+	Code    string
+	Message string
+}
+
+func (e *JobError) Error() string {
+	if e.State == "" {
+		return fmt.Sprintf("mts: job %s failed to submit: %s (%s)", e.JobID, e.Code, e.Message)
+	}
+	return fmt.Sprintf("mts: job %s %s: %s (%s)", e.JobID, e.State, e.Code, e.Message)
+}
+
+// Err returns a *JobError if the job failed or was
+// cancelled, or nil otherwise.
+func (j *JobInfo) Err() error {
+	if j.State != StateTranscodeFail && j.State != StateTranscodeCancelled {
+		return nil
+	}
+	return &JobError{
+		JobID:   j.JobID,
+		State:   j.State,
+		Code:    j.Code,
+		Message: j.Message,
+	}
+}
+
+// Err returns a *JobError if the job failed to submit,
+// or nil otherwise.
+func (r *JobResult) Err() error {
+	if r.Success {
+		return nil
+	}
+	return &JobError{
+		JobID:   r.Job.JobID,
+		Code:    r.Code,
+		Message: r.Message,
+	}
+}
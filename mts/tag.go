@@ -0,0 +1,25 @@
+package mts
+
+import (
+	"net/url"
+
+	"github.com/practigo/aliyun"
+)
+
+// AddMediaTagAPI returns a API for AddMediaTag, which tags
+// a media of the media library, e.g., one created by a
+// workflow execution.
+func AddMediaTagAPI(mediaID, tag string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "AddMediaTag")
+	a.v.Add("MediaId", mediaID)
+	a.v.Add("Tag", tag)
+
+	return a
+}
+
+// An AddMediaTagResponse contains the response for AddMediaTag.
+type AddMediaTagResponse struct {
+	RequestID string `json:"RequestId"`
+}
@@ -0,0 +1,241 @@
+package mts
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/practigo/aliyun"
+)
+
+// Template states.
+const (
+	TemplateNormal  = "Normal"
+	TemplateDeleted = "Deleted"
+)
+
+// A Segment is the segment setting for HLS outputs.
+type Segment struct {
+	Duration     string `json:"Duration,omitempty"`
+	ForceSegTime string `json:"ForceSegTime,omitempty"`
+}
+
+// A Gif is the setting for GIF outputs.
+type Gif struct {
+	Loop            string `json:"Loop,omitempty"`
+	FinalDelay      string `json:"FinalDelay,omitempty"`
+	IsCustomPalette string `json:"IsCustomPalette,omitempty"`
+	DitherMode      string `json:"DitherMode,omitempty"`
+}
+
+// A MuxConfig is the muxing setting of the container.
+type MuxConfig struct {
+	Segment *Segment `json:"Segment,omitempty"`
+	Gif     *Gif     `json:"Gif,omitempty"`
+}
+
+// A Template is a transcoding template.
+type Template struct {
+	ID          string       `json:"Id,omitempty"`
+	Name        string       `json:"Name"`
+	State       string       `json:"State,omitempty"`
+	Container   *Container   `json:"Container,omitempty"`
+	Video       *Video       `json:"Video,omitempty"`
+	Audio       *Audio       `json:"Audio,omitempty"`
+	MuxConfig   *MuxConfig   `json:"MuxConfig,omitempty"`
+	TransConfig *TransConfig `json:"TransConfig,omitempty"`
+}
+
+// fillTemplate fills the params of the template settings.
+func fillTemplate(v url.Values, t *Template) {
+	v.Add("Name", t.Name)
+
+	for k, c := range map[string]interface{}{
+		"Container":   t.Container,
+		"Video":       t.Video,
+		"Audio":       t.Audio,
+		"MuxConfig":   t.MuxConfig,
+		"TransConfig": t.TransConfig,
+	} {
+		if reflect.ValueOf(c).IsNil() {
+			continue
+		}
+		bs, _ := json.Marshal(c) // no error for strings
+		v.Add(k, string(bs))
+	}
+}
+
+// AddTemplateAPI returns a API for AddTemplate. The Name
+// is mandotory, and so is the Container for most formats.
+func AddTemplateAPI(t *Template) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "AddTemplate")
+	fillTemplate(a.v, t)
+
+	return a
+}
+
+// UpdateTemplateAPI returns a API for UpdateTemplate.
+// The ID & Name are mandotory.
+func UpdateTemplateAPI(t *Template) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "UpdateTemplate")
+	a.v.Add("TemplateId", t.ID)
+	fillTemplate(a.v, t)
+
+	return a
+}
+
+// QueryTemplatesAPI returns a API for QueryTemplateList.
+// It accepts one or more (at most 10) TemplateIDs for query.
+func QueryTemplatesAPI(id string, rest ...string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "QueryTemplateList")
+	a.v.Add("TemplateIds", joinIDs(id, rest))
+
+	return a
+}
+
+// SearchTemplatesAPI returns a API for SearchTemplate.
+// The state is "All" if empty; templates can be filtered
+// by the name prefix; page starts from 1 and size is 1 to 100.
+func SearchTemplatesAPI(state, prefix string, page, size int) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "SearchTemplate")
+
+	// optional
+	if state != "" {
+		a.v.Add("State", state)
+	}
+	if prefix != "" {
+		a.v.Add("NamePrefix", prefix)
+	}
+	if page > 0 {
+		a.v.Add("PageNumber", strconv.Itoa(page))
+	}
+	if size > 0 {
+		a.v.Add("PageSize", strconv.Itoa(size))
+	}
+
+	return a
+}
+
+// DeleteTemplateAPI returns a API for DeleteTemplate.
+func DeleteTemplateAPI(id string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "DeleteTemplate")
+	a.v.Add("TemplateId", id)
+
+	return a
+}
+
+// A TemplateResponse contains the response for
+// AddTemplate & UpdateTemplate.
+type TemplateResponse struct {
+	RequestID string   `json:"RequestId"`
+	Template  Template `json:"Template"`
+}
+
+// A QueryTemplatesResponse contains the response for
+// QueryTemplateList.
+type QueryTemplatesResponse struct {
+	RequestID    string `json:"RequestId"`
+	NonExistTids struct {
+		IDs []string `json:"String,omitempty"`
+	} `json:"NonExistTids"`
+	TemplateList struct {
+		Template []Template `json:"Template"`
+	} `json:"TemplateList"`
+}
+
+// A SearchTemplatesResponse contains the response for
+// SearchTemplate.
+type SearchTemplatesResponse struct {
+	RequestID    string `json:"RequestId"`
+	TotalCount   int    `json:"TotalCount"`
+	PageNumber   int    `json:"PageNumber"`
+	PageSize     int    `json:"PageSize"`
+	TemplateList struct {
+		Template []Template `json:"Template"`
+	} `json:"TemplateList"`
+}
+
+// A DeleteTemplateResponse contains the response for
+// DeleteTemplate.
+type DeleteTemplateResponse struct {
+	RequestID  string `json:"RequestId"`
+	TemplateID string `json:"TemplateId"`
+}
+
+// matches checks if all the values set in want are the
+// same in have, compared by their JSON forms.
+func matches(have, want interface{}) bool {
+	var h, w interface{}
+	bs, _ := json.Marshal(have)
+	json.Unmarshal(bs, &h)
+	bs, _ = json.Marshal(want)
+	json.Unmarshal(bs, &w)
+	return contains(h, w)
+}
+
+func contains(have, want interface{}) bool {
+	wm, ok := want.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(have, want)
+	}
+	hm, ok := have.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for k, v := range wm {
+		if !contains(hm[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// templatePageSize is the page size to search templates.
+const templatePageSize = 100
+
+// EnsureTemplate makes sure a normal template named after
+// the spec exists and matches the spec, by adding or updating
+// it if necessary. The values not set in the spec are left
+// to the server defaults. It returns the template as is.
+func EnsureTemplate(m TemplateManager, spec *Template) (Template, error) {
+	var found *Template
+	for page := 1; found == nil; page++ {
+		resp, err := m.SearchTemplates(TemplateNormal, spec.Name, page, templatePageSize)
+		if err != nil {
+			return Template{}, err
+		}
+		for i, t := range resp.TemplateList.Template {
+			if t.Name == spec.Name {
+				found = &resp.TemplateList.Template[i]
+				break
+			}
+		}
+		if page*templatePageSize >= resp.TotalCount {
+			break
+		}
+	}
+
+	if found == nil {
+		resp, err := m.AddTemplate(spec)
+		return resp.Template, err
+	}
+
+	want := *spec
+	want.ID, want.State = found.ID, found.State
+	if matches(found, &want) {
+		return *found, nil
+	}
+	resp, err := m.UpdateTemplate(&want)
+	return resp.Template, err
+}
@@ -0,0 +1,74 @@
+package mts_test
+
+import (
+	"testing"
+
+	"github.com/practigo/aliyun/mts"
+)
+
+// fakeTemplates keeps the templates in memory.
+type fakeTemplates struct {
+	mts.TemplateManager
+	ts      []mts.Template
+	added   int
+	updated int
+}
+
+func (f *fakeTemplates) SearchTemplates(state, prefix string, page, size int) (resp mts.SearchTemplatesResponse, err error) {
+	resp.TotalCount = len(f.ts)
+	resp.TemplateList.Template = f.ts
+	return
+}
+
+func (f *fakeTemplates) AddTemplate(t *mts.Template) (resp mts.TemplateResponse, err error) {
+	f.added++
+	resp.Template = *t
+	resp.Template.ID = "new"
+	// server defaults
+	resp.Template.State = mts.TemplateNormal
+	v := *t.Video
+	v.Remove = "false"
+	resp.Template.Video = &v
+	f.ts = append(f.ts, resp.Template)
+	return
+}
+
+func (f *fakeTemplates) UpdateTemplate(t *mts.Template) (resp mts.TemplateResponse, err error) {
+	f.updated++
+	resp.Template = *t
+	v := *t.Video
+	resp.Template.Video = &v
+	f.ts[0] = resp.Template
+	return
+}
+
+func TestEnsureTemplate(t *testing.T) {
+	f := &fakeTemplates{}
+	spec := &mts.Template{
+		Name:      "mp4-720p",
+		Container: &mts.Container{Format: "mp4"},
+		Video:     &mts.Video{Codec: "H.264", Width: "1280"},
+	}
+
+	for i := 0; i < 2; i++ {
+		tpl, err := mts.EnsureTemplate(f, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tpl.ID != "new" {
+			t.Errorf("unexpected template %+v", tpl)
+		}
+	}
+	if f.added != 1 || f.updated != 0 {
+		t.Errorf("want 1 add & 0 update, got %d & %d", f.added, f.updated)
+	}
+
+	spec.Video.Width = "1920"
+	tpl, err := mts.EnsureTemplate(f, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.updated != 1 || tpl.ID != "new" || tpl.Video.Width != "1920" {
+		t.Errorf("want updated template, got %+v", tpl)
+	}
+}
@@ -0,0 +1,181 @@
+package mts
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// MaxQueryJobs is the max number of JobIDs in a QueryJobList.
+const MaxQueryJobs = 10
+
+// default polling intervals
+const (
+	DefaultInterval    = 2 * time.Second
+	DefaultMaxInterval = 30 * time.Second
+)
+
+// A NonExistError reports the JobIDs that do not exist.
+type NonExistError struct {
+	IDs []string
+}
+
+func (e *NonExistError) Error() string {
+	return "mts: non-exist job ids: " + strings.Join(e.IDs, ",")
+}
+
+// A Waiter waits for the jobs to finish by polling.
+type Waiter struct {
+	// Querier is used by Wait for the transcoding jobs.
+	Querier Querier
+	// Interval is the initial polling interval, which is
+	// doubled after each round up to MaxInterval.
+	Interval    time.Duration
+	MaxInterval time.Duration
+	// Progress, if not nil, is called with the latest info
+	// of each job after each query, e.g., to report the
+	// Percent or to send the info to a channel.
+	Progress func(JobInfo)
+}
+
+func (w *Waiter) intervals() (d, max time.Duration) {
+	d, max = w.Interval, w.MaxInterval
+	if d <= 0 {
+		d = DefaultInterval
+	}
+	if max < d {
+		max = DefaultMaxInterval
+		if max < d {
+			max = d
+		}
+	}
+	return
+}
+
+// poll calls query with batches of at most MaxQueryJobs ids
+// with backoff until all ids are done. The query returns the
+// ids done in the batch.
+func (w *Waiter) poll(ctx context.Context, ids []string, query func([]string) ([]string, error)) error {
+	pending := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			pending = append(pending, id)
+		}
+	}
+
+	d, max := w.intervals()
+	for {
+		done := make(map[string]bool)
+		for i := 0; i < len(pending); i += MaxQueryJobs {
+			end := i + MaxQueryJobs
+			if end > len(pending) {
+				end = len(pending)
+			}
+			finished, err := query(pending[i:end])
+			if err != nil {
+				return err
+			}
+			for _, id := range finished {
+				done[id] = true
+			}
+		}
+
+		rest := pending[:0]
+		for _, id := range pending {
+			if !done[id] {
+				rest = append(rest, id)
+			}
+		}
+		pending = rest
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+		if d *= 2; d > max {
+			d = max
+		}
+	}
+}
+
+// A polledJob is a job in a query of waitJobs.
+type polledJob struct {
+	id       string
+	terminal bool
+	job      interface{}
+}
+
+// waitJobs polls the jobs until all of them are in a terminal
+// state, and returns the final jobs in the order of ids. The
+// query returns the non-exist ids and the jobs of a batch;
+// any non-exist id results in a *NonExistError.
+func (w *Waiter) waitJobs(ctx context.Context, ids []string, query func([]string) ([]string, []polledJob, error)) ([]interface{}, error) {
+	jobs := make(map[string]interface{}, len(ids))
+	err := w.poll(ctx, ids, func(batch []string) (done []string, err error) {
+		missing, polled, err := query(batch)
+		if err != nil {
+			return
+		}
+		if len(missing) > 0 {
+			return nil, &NonExistError{IDs: missing}
+		}
+		for _, j := range polled {
+			if j.terminal {
+				jobs[j.id] = j.job
+				done = append(done, j.id)
+			}
+		}
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]interface{}, len(ids))
+	for i, id := range ids {
+		res[i] = jobs[id]
+	}
+	return res, nil
+}
+
+// Wait polls the jobs until all of them are in a terminal
+// state, and returns the final infos in the order of ids.
+// Any non-exist id results in a *NonExistError.
+func (w *Waiter) Wait(ctx context.Context, ids ...string) ([]JobInfo, error) {
+	res, err := w.waitJobs(ctx, ids, func(batch []string) ([]string, []polledJob, error) {
+		resp, err := w.Querier.Query(batch[0], batch[1:]...)
+		if err != nil {
+			return nil, nil, err
+		}
+		polled := make([]polledJob, len(resp.JobList.Job))
+		for i, j := range resp.JobList.Job {
+			if w.Progress != nil {
+				w.Progress(j)
+			}
+			polled[i] = polledJob{j.JobID, IsTerminal(j.State), j}
+		}
+		return resp.NonExistJobIDs.IDs, polled, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]JobInfo, len(res))
+	for i, j := range res {
+		jobs[i] = j.(JobInfo)
+	}
+	return jobs, nil
+}
+
+// Wait waits for the jobs using a Waiter with the
+// default intervals.
+func Wait(ctx context.Context, q Querier, ids ...string) ([]JobInfo, error) {
+	w := &Waiter{Querier: q}
+	return w.Wait(ctx, ids...)
+}
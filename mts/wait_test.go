@@ -0,0 +1,104 @@
+package mts_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/practigo/aliyun/mts"
+)
+
+// stages moves a job through the states by the number of
+// queries: queued on the first, running on the second and
+// done from the third on.
+type stages struct {
+	queried int
+}
+
+func (s *stages) next(queued, running, done string) string {
+	s.queried++
+	switch s.queried {
+	case 1:
+		return queued
+	case 2:
+		return running
+	}
+	return done
+}
+
+// fakeQuerier finishes each job after it's queried n times.
+type fakeQuerier struct {
+	n       int
+	queried map[string]int
+	batches []int
+}
+
+func (q *fakeQuerier) Query(id string, rest ...string) (resp mts.QueryJobsResponse, err error) {
+	ids := append([]string{id}, rest...)
+	q.batches = append(q.batches, len(ids))
+	for _, id := range ids {
+		if id == "missing" {
+			resp.NonExistJobIDs.IDs = append(resp.NonExistJobIDs.IDs, id)
+			continue
+		}
+		q.queried[id]++
+		j := mts.JobInfo{JobID: id, State: "Transcoding", Percent: 100 * q.queried[id] / q.n}
+		if q.queried[id] >= q.n {
+			j.State = mts.StateTranscodeSuccess
+		}
+		resp.JobList.Job = append(resp.JobList.Job, j)
+	}
+	return
+}
+
+func TestWait(t *testing.T) {
+	q := &fakeQuerier{n: 2, queried: make(map[string]int)}
+	ids := make([]string, 12)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("job-%d", i)
+	}
+
+	progress := 0
+	w := &mts.Waiter{
+		Querier:  q,
+		Interval: time.Millisecond,
+		Progress: func(mts.JobInfo) { progress++ },
+	}
+	jobs, err := w.Wait(context.Background(), ids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, j := range jobs {
+		if j.JobID != ids[i] || j.State != mts.StateTranscodeSuccess {
+			t.Errorf("unexpected job %d: %+v", i, j)
+		}
+	}
+	// 2 rounds of 10 + 2
+	if fmt.Sprint(q.batches) != "[10 2 10 2]" {
+		t.Errorf("unexpected batches %v", q.batches)
+	}
+	if progress != 24 {
+		t.Errorf("want 24 progress calls, got %d", progress)
+	}
+}
+
+func TestWaitNonExist(t *testing.T) {
+	q := &fakeQuerier{n: 1, queried: make(map[string]int)}
+	_, err := mts.Wait(context.Background(), q, "job", "missing")
+	var ne *mts.NonExistError
+	if !errors.As(err, &ne) || ne.IDs[0] != "missing" {
+		t.Errorf("want NonExistError, got %v", err)
+	}
+}
+
+func TestWaitCancel(t *testing.T) {
+	q := &fakeQuerier{n: 100, queried: make(map[string]int)}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	w := &mts.Waiter{Querier: q, Interval: time.Millisecond}
+	if _, err := w.Wait(ctx, "job"); err != context.DeadlineExceeded {
+		t.Errorf("want deadline exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package mts
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/practigo/aliyun"
+)
+
+// Watermark types & reference positions.
+const (
+	WaterMarkImage = "Image"
+	WaterMarkText  = "Text"
+
+	TopLeft     = "TopLeft"
+	TopRight    = "TopRight"
+	BottomLeft  = "BottomLeft"
+	BottomRight = "BottomRight"
+)
+
+// A Timeline is the period to show the watermark,
+// e.g., Start "0" & Duration "ToEND".
+type Timeline struct {
+	Start    string `json:"Start,omitempty"`
+	Duration string `json:"Duration,omitempty"`
+}
+
+// A WaterMarkConfig is the config of a watermark template.
+// Width & Height are either pixels or ratios of the output,
+// and so are the offsets Dx & Dy relative to the ReferPos.
+type WaterMarkConfig struct {
+	Width    string    `json:"Width,omitempty"`
+	Height   string    `json:"Height,omitempty"`
+	Dx       string    `json:"Dx,omitempty"`
+	Dy       string    `json:"Dy,omitempty"`
+	ReferPos string    `json:"ReferPos,omitempty"`
+	Type     string    `json:"Type,omitempty"`
+	Timeline *Timeline `json:"Timeline,omitempty"`
+}
+
+// A WaterMarkTemplate is a watermark template.
+type WaterMarkTemplate struct {
+	ID    string `json:"Id,omitempty"`
+	Name  string `json:"Name"`
+	State string `json:"State,omitempty"`
+	WaterMarkConfig
+}
+
+// NewWaterMark returns a WaterMark for the output using the
+// template and the logo image on OSS.
+func NewWaterMark(templateID string, logo aliyun.OSS) WaterMark {
+	in := FromOSS(logo)
+	return WaterMark{
+		InputFile:           &in,
+		WaterMarkTemplateID: templateID,
+	}
+}
+
+func fillWaterMark(v url.Values, t *WaterMarkTemplate) {
+	v.Add("Name", t.Name)
+	bs, _ := json.Marshal(t.WaterMarkConfig) // no error for strings
+	v.Add("Config", string(bs))
+}
+
+// AddWaterMarkTemplateAPI returns a API for AddWaterMarkTemplate.
+func AddWaterMarkTemplateAPI(t *WaterMarkTemplate) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "AddWaterMarkTemplate")
+	fillWaterMark(a.v, t)
+
+	return a
+}
+
+// UpdateWaterMarkTemplateAPI returns a API for UpdateWaterMarkTemplate.
+func UpdateWaterMarkTemplateAPI(t *WaterMarkTemplate) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "UpdateWaterMarkTemplate")
+	a.v.Add("WaterMarkTemplateId", t.ID)
+	fillWaterMark(a.v, t)
+
+	return a
+}
+
+// QueryWaterMarkTemplatesAPI returns a API for QueryWaterMarkTemplateList.
+// It accepts one or more (at most 10) IDs for query.
+func QueryWaterMarkTemplatesAPI(id string, rest ...string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "QueryWaterMarkTemplateList")
+	a.v.Add("WaterMarkTemplateIds", joinIDs(id, rest))
+
+	return a
+}
+
+// DeleteWaterMarkTemplateAPI returns a API for DeleteWaterMarkTemplate.
+func DeleteWaterMarkTemplateAPI(id string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "DeleteWaterMarkTemplate")
+	a.v.Add("WaterMarkTemplateId", id)
+
+	return a
+}
+
+// A WaterMarkTemplateResponse contains the response for
+// AddWaterMarkTemplate & UpdateWaterMarkTemplate.
+type WaterMarkTemplateResponse struct {
+	RequestID         string            `json:"RequestId"`
+	WaterMarkTemplate WaterMarkTemplate `json:"WaterMarkTemplate"`
+}
+
+// A QueryWaterMarkTemplatesResponse contains the response for
+// QueryWaterMarkTemplateList.
+type QueryWaterMarkTemplatesResponse struct {
+	RequestID    string `json:"RequestId"`
+	NonExistWids struct {
+		IDs []string `json:"String,omitempty"`
+	} `json:"NonExistWids"`
+	WaterMarkTemplateList struct {
+		WaterMarkTemplate []WaterMarkTemplate `json:"WaterMarkTemplate"`
+	} `json:"WaterMarkTemplateList"`
+}
+
+// A DeleteWaterMarkTemplateResponse contains the response for
+// DeleteWaterMarkTemplate.
+type DeleteWaterMarkTemplateResponse struct {
+	RequestID           string `json:"RequestId"`
+	WaterMarkTemplateID string `json:"WaterMarkTemplateId"`
+}
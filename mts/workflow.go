@@ -0,0 +1,398 @@
+package mts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/practigo/aliyun"
+)
+
+// Activity types.
+const (
+	ActivityStart     = "Start"
+	ActivityTranscode = "Transcode"
+	ActivitySnapshot  = "Snapshot"
+	ActivityReport    = "Report"
+)
+
+// Workflow trigger modes.
+const (
+	OssAutoTrigger = "OssAutoTrigger"
+	NotInAuto      = "NotInAuto"
+)
+
+// An Activity is a node of a workflow. The Parameters are
+// strings, some of which are JSON themselves, e.g., the
+// Outputs of a Transcode activity.
+type Activity struct {
+	Name       string            `json:"Name"`
+	Type       string            `json:"Type"`
+	Parameters map[string]string `json:"Parameters"`
+}
+
+// A WorkflowInput is where a workflow watches for
+// the new uploads.
+type WorkflowInput struct {
+	Bucket       string `json:"Bucket"`
+	Location     string `json:"Location"`
+	ObjectPrefix string `json:"ObjectPrefix"`
+}
+
+// StartActivity returns the Start activity which runs the
+// workflow in the pipeline for the input.
+func StartActivity(name, pipelineID string, in WorkflowInput) Activity {
+	bs, _ := json.Marshal(in) // no error for strings
+	return Activity{
+		Name: name,
+		Type: ActivityStart,
+		Parameters: map[string]string{
+			"PipelineId": pipelineID,
+			"InputFile":  string(bs),
+		},
+	}
+}
+
+// TranscodeActivity returns a Transcode activity with the
+// validated outputs to the bucket at the location.
+func TranscodeActivity(name, bucket, location string, outs ...Output) (Activity, error) {
+	var r SubmitJobsRequest
+	if err := r.SetOutputs(outs...); err != nil {
+		return Activity{}, err
+	}
+	return Activity{
+		Name: name,
+		Type: ActivityTranscode,
+		Parameters: map[string]string{
+			"OutputBucket":   bucket,
+			"OutputLocation": location,
+			"Outputs":        r.Outputs,
+		},
+	}, nil
+}
+
+// SnapshotActivity returns a Snapshot activity with the
+// validated config.
+func SnapshotActivity(name string, cfg SnapshotConfig) (Activity, error) {
+	if err := cfg.Validate(); err != nil {
+		return Activity{}, err
+	}
+	bs, err := json.Marshal(cfg)
+	if err != nil {
+		return Activity{}, err
+	}
+	return Activity{
+		Name: name,
+		Type: ActivitySnapshot,
+		Parameters: map[string]string{
+			"SnapshotConfig": string(bs),
+		},
+	}, nil
+}
+
+// ReportActivity returns the Report activity which ends
+// the workflow.
+func ReportActivity(name string) Activity {
+	return Activity{
+		Name:       name,
+		Type:       ActivityReport,
+		Parameters: map[string]string{},
+	}
+}
+
+// A Topology is the graph of the activities of a workflow,
+// where the Dependencies map an activity to the ones
+// running after it.
+type Topology struct {
+	Activities   map[string]Activity `json:"Activities"`
+	Dependencies map[string][]string `json:"Dependencies"`
+}
+
+// NewTopology returns a Topology with the start activity.
+func NewTopology(start Activity) *Topology {
+	t := &Topology{
+		Activities:   make(map[string]Activity),
+		Dependencies: make(map[string][]string),
+	}
+	return t.Add(start)
+}
+
+// Add adds the activity to run after the parents.
+func (t *Topology) Add(a Activity, parents ...string) *Topology {
+	t.Activities[a.Name] = a
+	if _, ok := t.Dependencies[a.Name]; !ok {
+		t.Dependencies[a.Name] = []string{} // [] instead of null
+	}
+	for _, p := range parents {
+		t.Dependencies[p] = append(t.Dependencies[p], a.Name)
+	}
+	return t
+}
+
+// Validate checks that the topology is a DAG starting from
+// the only Start activity, in which every activity is
+// reachable and leads to the only Report activity.
+func (t *Topology) Validate() error {
+	var start, report string
+	for name, a := range t.Activities {
+		if name != a.Name {
+			return fmt.Errorf("mts: activity %s is named %s", name, a.Name)
+		}
+		switch a.Type {
+		case ActivityStart, ActivityReport:
+			if a.Type == ActivityStart && start != "" || a.Type == ActivityReport && report != "" {
+				return fmt.Errorf("mts: more than one %s activity", a.Type)
+			}
+			if a.Type == ActivityStart {
+				start = name
+			} else {
+				report = name
+			}
+		case ActivityTranscode, ActivitySnapshot:
+		default:
+			return fmt.Errorf("mts: activity %s has unknown type %s", name, a.Type)
+		}
+	}
+	if start == "" || report == "" {
+		return fmt.Errorf("mts: want both %s & %s activities", ActivityStart, ActivityReport)
+	}
+
+	parents := make(map[string]int)
+	for name, children := range t.Dependencies {
+		if _, ok := t.Activities[name]; !ok {
+			return fmt.Errorf("mts: dependency of unknown activity %s", name)
+		}
+		for _, c := range children {
+			if _, ok := t.Activities[c]; !ok {
+				return fmt.Errorf("mts: %s depends on unknown activity %s", name, c)
+			}
+			parents[c]++
+		}
+	}
+	if parents[start] > 0 {
+		return fmt.Errorf("mts: %s activity %s has parents", ActivityStart, start)
+	}
+	if len(t.Dependencies[report]) > 0 {
+		return fmt.Errorf("mts: %s activity %s has children", ActivityReport, report)
+	}
+
+	// DFS from start for cycles & reachability
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var visit func(string) error
+	visit = func(name string) error {
+		state[name] = visiting
+		children := t.Dependencies[name]
+		if len(children) == 0 && name != report {
+			return fmt.Errorf("mts: activity %s does not lead to %s", name, ActivityReport)
+		}
+		for _, c := range children {
+			switch state[c] {
+			case visiting:
+				return fmt.Errorf("mts: cycle at activity %s", c)
+			case 0:
+				if err := visit(c); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+	if err := visit(start); err != nil {
+		return err
+	}
+	for name := range t.Activities {
+		if state[name] != visited {
+			return fmt.Errorf("mts: activity %s is not reachable", name)
+		}
+	}
+	return nil
+}
+
+// marshal validates and marshals the topology.
+func (t *Topology) marshal() (string, error) {
+	if err := t.Validate(); err != nil {
+		return "", err
+	}
+	bs, err := json.Marshal(t)
+	return string(bs), err
+}
+
+// AddMediaWorkflowAPI returns a API for AddMediaWorkflow.
+// The topology is validated before. The triggerMode is
+// OssAutoTrigger if empty.
+func AddMediaWorkflowAPI(name string, t *Topology, triggerMode string) (aliyun.API, error) {
+	topology, err := t.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "AddMediaWorkflow")
+	a.v.Add("Name", name)
+	a.v.Add("Topology", topology)
+
+	// optional
+	if triggerMode != "" {
+		a.v.Add("TriggerMode", triggerMode)
+	}
+
+	return a, nil
+}
+
+// UpdateMediaWorkflowAPI returns a API for UpdateMediaWorkflow.
+// The topology is validated before.
+func UpdateMediaWorkflowAPI(id string, t *Topology) (aliyun.API, error) {
+	topology, err := t.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "UpdateMediaWorkflow")
+	a.v.Add("MediaWorkflowId", id)
+	a.v.Add("Topology", topology)
+
+	return a, nil
+}
+
+// QueryMediaWorkflowsAPI returns a API for QueryMediaWorkflowList.
+// It accepts one or more (at most 10) IDs for query.
+func QueryMediaWorkflowsAPI(id string, rest ...string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "QueryMediaWorkflowList")
+	a.v.Add("MediaWorkflowIds", joinIDs(id, rest))
+
+	return a
+}
+
+// DeactivateMediaWorkflowAPI returns a API for DeactivateMediaWorkflow.
+func DeactivateMediaWorkflowAPI(id string) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "DeactivateMediaWorkflow")
+	a.v.Add("MediaWorkflowId", id)
+
+	return a
+}
+
+// A ListWorkflowExecutionsRequest contains the param for
+// ListMediaWorkflowExecutions. Either the ID or the Name
+// of the workflow is mandotory.
+type ListWorkflowExecutionsRequest struct {
+	ID           string
+	Name         string
+	InputFileURL string
+	// PageSize is 1 to 100, default to 10.
+	PageSize      int
+	NextPageToken string
+}
+
+// ListWorkflowExecutionsAPI returns a API for ListMediaWorkflowExecutions.
+func ListWorkflowExecutionsAPI(r *ListWorkflowExecutionsRequest) aliyun.API {
+	a := &api{v: url.Values{}}
+
+	a.v.Add("Action", "ListMediaWorkflowExecutions")
+
+	// optional
+	if r.ID != "" {
+		a.v.Add("MediaWorkflowId", r.ID)
+	}
+	if r.Name != "" {
+		a.v.Add("MediaWorkflowName", r.Name)
+	}
+	if r.InputFileURL != "" {
+		a.v.Add("InputFileURL", r.InputFileURL)
+	}
+	if r.PageSize > 0 {
+		a.v.Add("MaximumPageSize", strconv.Itoa(r.PageSize))
+	}
+	if r.NextPageToken != "" {
+		a.v.Add("NextPageToken", r.NextPageToken)
+	}
+
+	return a
+}
+
+// A MediaWorkflow is a MTS media workflow. The Topology is
+// a JSON string; use ParseTopology to decode it.
+type MediaWorkflow struct {
+	ID           string `json:"MediaWorkflowId"`
+	Name         string `json:"Name"`
+	State        string `json:"State"`
+	TriggerMode  string `json:"TriggerMode"`
+	Topology     string `json:"Topology"`
+	CreationTime string `json:"CreationTime"`
+}
+
+// ParseTopology decodes the Topology.
+func (w *MediaWorkflow) ParseTopology() (t Topology, err error) {
+	err = json.Unmarshal([]byte(w.Topology), &t)
+	return
+}
+
+// An ActivityExecution is the execution of an activity.
+type ActivityExecution struct {
+	Name      string `json:"Name"`
+	Type      string `json:"Type"`
+	JobID     string `json:"JobId"`
+	State     string `json:"State"`
+	Code      string `json:"Code"`
+	Message   string `json:"Message"`
+	StartTime string `json:"StartTime"`
+	EndTime   string `json:"EndTime"`
+}
+
+// A WorkflowExecution is an execution of a workflow.
+type WorkflowExecution struct {
+	RunID           string `json:"RunId"`
+	MediaWorkflowID string `json:"MediaWorkflowId"`
+	Name            string `json:"Name"`
+	State           string `json:"State"`
+	MediaID         string `json:"MediaId"`
+	CreationTime    string `json:"CreationTime"`
+	Input           struct {
+		InputFile JobIO  `json:"InputFile"`
+		UserData  string `json:"UserData"`
+	} `json:"Input"`
+	ActivityList struct {
+		Activity []ActivityExecution `json:"Activity"`
+	} `json:"ActivityList"`
+}
+
+// A MediaWorkflowResponse contains the response for
+// AddMediaWorkflow, UpdateMediaWorkflow & DeactivateMediaWorkflow.
+type MediaWorkflowResponse struct {
+	RequestID     string        `json:"RequestId"`
+	MediaWorkflow MediaWorkflow `json:"MediaWorkflow"`
+}
+
+// A QueryMediaWorkflowsResponse contains the response for
+// QueryMediaWorkflowList.
+type QueryMediaWorkflowsResponse struct {
+	RequestID                string `json:"RequestId"`
+	NonExistMediaWorkflowIDs struct {
+		IDs []string `json:"String,omitempty"`
+	} `json:"NonExistMediaWorkflowIds"`
+	MediaWorkflowList struct {
+		MediaWorkflow []MediaWorkflow `json:"MediaWorkflow"`
+	} `json:"MediaWorkflowList"`
+}
+
+// A ListWorkflowExecutionsResponse contains the response for
+// ListMediaWorkflowExecutions.
+type ListWorkflowExecutionsResponse struct {
+	RequestID                  string `json:"RequestId"`
+	NextPageToken              string `json:"NextPageToken"`
+	MediaWorkflowExecutionList struct {
+		MediaWorkflowExecution []WorkflowExecution `json:"MediaWorkflowExecution"`
+	} `json:"MediaWorkflowExecutionList"`
+}
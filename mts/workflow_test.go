@@ -0,0 +1,85 @@
+package mts_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/practigo/aliyun/mts"
+)
+
+func testTopology(t *testing.T) *mts.Topology {
+	start := mts.StartActivity("Act-Start", "pipeline", mts.WorkflowInput{
+		Bucket:       "example-bucket",
+		Location:     "oss-cn-hangzhou",
+		ObjectPrefix: "uploads/",
+	})
+	transcode, err := mts.TranscodeActivity("Act-Transcode", "example-bucket", "oss-cn-hangzhou", mts.Output{
+		OutputObject: mts.EscapeObject("transcode/{ObjectPrefix}{FileName}.m3u8"),
+		TemplateID:   "S00000001-100020",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := mts.SnapshotActivity("Act-Snapshot", mts.SnapshotConfig{
+		OutputFile: mts.JobIO{Bucket: "example-bucket", Location: "oss-cn-hangzhou", Object: "snapshot.jpg"},
+		Time:       "5",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return mts.NewTopology(start).
+		Add(transcode, "Act-Start").
+		Add(snapshot, "Act-Start").
+		Add(mts.ReportActivity("Act-Report"), "Act-Transcode", "Act-Snapshot")
+}
+
+func TestTopology(t *testing.T) {
+	top := testTopology(t)
+	if err := top.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	bs, err := json.Marshal(top)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := mts.MediaWorkflow{Topology: string(bs)}
+	parsed, err := w.ParseTopology()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = parsed.Validate(); err != nil {
+		t.Errorf("parsed topology should be valid: %v", err)
+	}
+	if deps := parsed.Dependencies["Act-Report"]; deps == nil || len(deps) != 0 {
+		t.Errorf("report should have empty dependencies, got %v", deps)
+	}
+}
+
+func TestTopologyInvalid(t *testing.T) {
+	cycle := testTopology(t)
+	cycle.Dependencies["Act-Transcode"] = append(cycle.Dependencies["Act-Transcode"], "Act-Snapshot")
+	cycle.Dependencies["Act-Snapshot"] = append(cycle.Dependencies["Act-Snapshot"], "Act-Transcode")
+
+	dangling := testTopology(t).Add(mts.Activity{Name: "Act-Orphan", Type: mts.ActivitySnapshot})
+
+	deadEnd := testTopology(t)
+	deadEnd.Dependencies["Act-Snapshot"] = []string{}
+
+	noReport := testTopology(t)
+	delete(noReport.Activities, "Act-Report")
+
+	for name, top := range map[string]*mts.Topology{
+		"cycle":     cycle,
+		"dangling":  dangling,
+		"dead end":  deadEnd,
+		"no report": noReport,
+	} {
+		if err := top.Validate(); err == nil {
+			t.Errorf("%s: should be invalid", name)
+		} else {
+			t.Log(name, err)
+		}
+	}
+}
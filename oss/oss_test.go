@@ -0,0 +1,119 @@
+package oss_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/practigo/aliyun"
+	"github.com/practigo/aliyun/oss"
+	"github.com/practigo/aliyun/sts"
+)
+
+var (
+	testKey = oss.FromCredentials(sts.Credentials{
+		AccessKeyID:     "id",
+		AccessKeySecret: "secret",
+		SecurityToken:   "token",
+	})
+	testLoc = aliyun.OSS{
+		Bucket:   "example-bucket",
+		Endpoint: "oss-cn-hangzhou.aliyuncs.com",
+		Object:   "dir/中文 name.png",
+	}
+)
+
+func TestRegion(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"oss-cn-hangzhou.aliyuncs.com":          "cn-hangzhou",
+		"oss-cn-shanghai-internal.aliyuncs.com": "cn-shanghai",
+	} {
+		if got := oss.Region(endpoint); got != want {
+			t.Errorf("Region(%s) = %s, want %s", endpoint, got, want)
+		}
+	}
+}
+
+func TestSignV1(t *testing.T) {
+	raw := testKey.SignV1(testLoc, oss.Presign{Method: "PUT", ContentType: "image/png"})
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "example-bucket.oss-cn-hangzhou.aliyuncs.com" {
+		t.Errorf("unexpected host %s", u.Host)
+	}
+	if u.Path != "/"+testLoc.Object {
+		t.Errorf("unexpected path %s", u.Path)
+	}
+	q := u.Query()
+	for _, k := range []string{"OSSAccessKeyId", "Expires", "Signature", "security-token"} {
+		if q.Get(k) == "" {
+			t.Errorf("missing %s in %s", k, raw)
+		}
+	}
+}
+
+func TestSignV4(t *testing.T) {
+	raw := testKey.SignV4(testLoc, oss.Presign{Expires: 30 * 24 * time.Hour})
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if !strings.HasSuffix(q.Get("x-oss-credential"), "/cn-hangzhou/oss/aliyun_v4_request") {
+		t.Errorf("unexpected credential %s", q.Get("x-oss-credential"))
+	}
+	if q.Get("x-oss-expires") != "604800" {
+		t.Errorf("expires should be capped, got %s", q.Get("x-oss-expires"))
+	}
+	if len(q.Get("x-oss-signature")) != 64 {
+		t.Errorf("unexpected signature %s", q.Get("x-oss-signature"))
+	}
+}
+
+func TestPost(t *testing.T) {
+	p := oss.PostPolicy{
+		KeyPrefix:   "uploads/",
+		ContentType: "image/png",
+		MaxSize:     1 << 20,
+	}
+	o := testLoc
+	o.Object = ""
+
+	for name, f := range map[string]func(aliyun.OSS, oss.PostPolicy) (oss.PostForm, error){
+		"v1": testKey.PostV1,
+		"v4": testKey.PostV4,
+	} {
+		form, err := f(o, p)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if form.Fields["key"] != "uploads/" {
+			t.Errorf("%s: unexpected key %s", name, form.Fields["key"])
+		}
+
+		bs, err := base64.StdEncoding.DecodeString(form.Fields["policy"])
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		var policy struct {
+			Expiration string        `json:"expiration"`
+			Conditions []interface{} `json:"conditions"`
+		}
+		if err = json.Unmarshal(bs, &policy); err != nil {
+			t.Fatal(name, err)
+		}
+		// bucket, key, content-type, size & token
+		want := 5
+		if name == "v4" {
+			want += 3
+		}
+		if len(policy.Conditions) != want {
+			t.Errorf("%s: want %d conditions, got %s", name, want, bs)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package oss
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/practigo/aliyun"
+)
+
+// PolicyTimeFormat is the time format of the policy expiration.
+const PolicyTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// A PostPolicy restricts a browser PostObject upload, see
+// https://help.aliyun.com/document_detail/31988.html.
+type PostPolicy struct {
+	// Expires is the lifetime of the policy,
+	// DefaultExpires if not set.
+	Expires time.Duration
+	// KeyPrefix allows any key with the prefix; if not set,
+	// the key must be the Object of the location.
+	KeyPrefix string
+	// ContentType is the exact Content-Type if set.
+	ContentType string
+	// MinSize & MaxSize limit the content length
+	// if MaxSize > 0.
+	MinSize, MaxSize int64
+	// SuccessStatus is the success_action_status if set,
+	// e.g., 201.
+	SuccessStatus int
+}
+
+// A PostForm contains the URL to post to and the form
+// fields to send along with the file.
+type PostForm struct {
+	URL    string            `json:"url"`
+	Fields map[string]string `json:"fields"`
+}
+
+// policy returns the base64 encoded policy with the extra
+// conditions, and fills the fields accordingly.
+func (p PostPolicy) policy(o aliyun.OSS, now time.Time, fields map[string]string, extra map[string]string) (string, error) {
+	expires := p.Expires
+	if expires <= 0 {
+		expires = DefaultExpires
+	}
+
+	conds := []interface{}{
+		map[string]string{"bucket": o.Bucket},
+	}
+	fields["key"] = o.Object
+	if p.KeyPrefix != "" {
+		conds = append(conds, []string{"starts-with", "$key", p.KeyPrefix})
+		if o.Object == "" {
+			fields["key"] = p.KeyPrefix
+		}
+	} else {
+		conds = append(conds, []string{"eq", "$key", o.Object})
+	}
+	if p.ContentType != "" {
+		conds = append(conds, []string{"eq", "$Content-Type", p.ContentType})
+		fields["Content-Type"] = p.ContentType
+	}
+	if p.MaxSize > 0 {
+		conds = append(conds, []interface{}{"content-length-range", p.MinSize, p.MaxSize})
+	}
+	if p.SuccessStatus > 0 {
+		status := strconv.Itoa(p.SuccessStatus)
+		conds = append(conds, map[string]string{"success_action_status": status})
+		fields["success_action_status"] = status
+	}
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // a stable policy to sign
+	for _, k := range keys {
+		conds = append(conds, map[string]string{k: extra[k]})
+		fields[k] = extra[k]
+	}
+
+	bs, err := json.Marshal(map[string]interface{}{
+		"expiration": now.Add(expires).UTC().Format(PolicyTimeFormat),
+		"conditions": conds,
+	})
+	if err != nil {
+		return "", err
+	}
+	policy := base64.StdEncoding.EncodeToString(bs)
+	fields["policy"] = policy
+	return policy, nil
+}
+
+// PostV1 returns the form to upload to the location o
+// with the V1 signature.
+func (k *AccessKey) PostV1(o aliyun.OSS, p PostPolicy) (PostForm, error) {
+	return k.postV1(o, p, time.Now())
+}
+
+func (k *AccessKey) postV1(o aliyun.OSS, p PostPolicy, now time.Time) (form PostForm, err error) {
+	fields := make(map[string]string)
+	extra := make(map[string]string)
+	if k.SecurityToken != "" {
+		extra["x-oss-security-token"] = k.SecurityToken
+	}
+
+	policy, err := p.policy(o, now, fields, extra)
+	if err != nil {
+		return
+	}
+	fields["OSSAccessKeyId"] = k.AccessKeyID
+	fields["Signature"] = base64.StdEncoding.EncodeToString(hmacSHA1(k.AccessKeySecret, policy))
+
+	return PostForm{URL: BucketURL(o), Fields: fields}, nil
+}
+
+// PostV4 returns the form to upload to the location o
+// with the V4 signature.
+func (k *AccessKey) PostV4(o aliyun.OSS, p PostPolicy) (PostForm, error) {
+	return k.postV4(o, p, time.Now())
+}
+
+func (k *AccessKey) postV4(o aliyun.OSS, p PostPolicy, now time.Time) (form PostForm, err error) {
+	region := Region(o.Endpoint)
+
+	fields := make(map[string]string)
+	extra := map[string]string{
+		"x-oss-signature-version": V4Algorithm,
+		"x-oss-credential":        k.AccessKeyID + "/" + scope(now, region),
+		"x-oss-date":              now.UTC().Format(V4DateTime),
+	}
+	if k.SecurityToken != "" {
+		extra["x-oss-security-token"] = k.SecurityToken
+	}
+
+	policy, err := p.policy(o, now, fields, extra)
+	if err != nil {
+		return
+	}
+	fields["x-oss-signature"] = hex.EncodeToString(hmacSHA256(k.signingKey(now, region), policy))
+
+	return PostForm{URL: BucketURL(o), Fields: fields}, nil
+}
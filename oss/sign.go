@@ -0,0 +1,261 @@
+package oss
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/practigo/aliyun"
+	"github.com/practigo/aliyun/sts"
+)
+
+// signing constants
+const (
+	V4Algorithm = "OSS4-HMAC-SHA256"
+	V4Request   = "aliyun_v4_request"
+	V4Payload   = "UNSIGNED-PAYLOAD"
+	V4Date      = "20060102"
+	V4DateTime  = "20060102T150405Z"
+
+	DefaultExpires = time.Hour
+	MaxV4Expires   = 7 * 24 * time.Hour
+)
+
+// AccessKey signs the OSS requests. It's defined on
+// sts.Credentials to take the temporary credentials
+// directly; the SecurityToken is optional so it works
+// for a plain id-secret pair as well.
+type AccessKey sts.Credentials
+
+// NewAccessKey returns an AccessKey of the id-secret pair.
+func NewAccessKey(id, secret string) *AccessKey {
+	return &AccessKey{
+		AccessKeyID:     id,
+		AccessKeySecret: secret,
+	}
+}
+
+// FromCredentials returns an AccessKey of the temporary
+// credentials obtained from STS.
+func FromCredentials(c sts.Credentials) *AccessKey {
+	k := AccessKey(c)
+	return &k
+}
+
+// A Presign describes a request to be presigned.
+type Presign struct {
+	// Method is GET if not set. PUT is for uploading.
+	Method string
+	// ContentType is optional; if set, the request must be
+	// sent with the same Content-Type header.
+	ContentType string
+	// Expires is the lifetime of the URL, DefaultExpires
+	// if not set.
+	Expires time.Duration
+}
+
+func (p Presign) method() string {
+	if p.Method == "" {
+		return "GET"
+	}
+	return p.Method
+}
+
+func (p Presign) expires() time.Duration {
+	if p.Expires <= 0 {
+		return DefaultExpires
+	}
+	return p.Expires
+}
+
+// Region returns the region id for the OSS endpoint,
+// e.g., cn-hangzhou for oss-cn-hangzhou.aliyuncs.com.
+func Region(endpoint string) string {
+	r := strings.TrimPrefix(endpoint, "oss-")
+	if i := strings.Index(r, "."); i >= 0 {
+		r = r[:i]
+	}
+	return strings.TrimSuffix(r, "-internal")
+}
+
+// BucketURL returns the virtual-hosted style URL of the bucket.
+func BucketURL(o aliyun.OSS) string {
+	return "https://" + o.Bucket + "." + o.Endpoint
+}
+
+// ObjectURL returns the unsigned URL of the object.
+func ObjectURL(o aliyun.OSS) string {
+	return BucketURL(o) + "/" + encode(o.Object, true)
+}
+
+// encode percent-encodes s except the unreserved characters,
+// and '/' if keepSlash is true.
+func encode(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA1(key, data string) []byte {
+	h := hmac.New(sha1.New, []byte(key))
+	h.Write([]byte(data)) // sha1 Write() returns no error
+	return h.Sum(nil)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// SignV1 returns a presigned URL using the V1 signature, see
+// https://help.aliyun.com/document_detail/31952.html.
+func (k *AccessKey) SignV1(o aliyun.OSS, p Presign) string {
+	return k.signV1(o, p, time.Now())
+}
+
+func (k *AccessKey) signV1(o aliyun.OSS, p Presign, now time.Time) string {
+	expires := strconv.FormatInt(now.Add(p.expires()).Unix(), 10)
+
+	resource := "/" + o.Bucket + "/" + o.Object
+	if k.SecurityToken != "" {
+		resource += "?security-token=" + k.SecurityToken
+	}
+	signature := k.signatureV1(p.method(), p.ContentType, expires, resource)
+
+	v := url.Values{}
+	v.Set("OSSAccessKeyId", k.AccessKeyID)
+	v.Set("Expires", expires)
+	v.Set("Signature", signature)
+	if k.SecurityToken != "" {
+		v.Set("security-token", k.SecurityToken)
+	}
+	return ObjectURL(o) + "?" + v.Encode()
+}
+
+// signatureV1 signs the V1 string of a presigned URL, in
+// which the Expires takes the place of the Date.
+func (k *AccessKey) signatureV1(method, contentType, expires, resource string) string {
+	// VERB + "\n" + Content-MD5 + "\n" + Content-Type + "\n"
+	// + Expires + "\n" + CanonicalizedResource
+	toSign := method + "\n\n" + contentType + "\n" + expires + "\n" + resource
+	return base64.StdEncoding.EncodeToString(hmacSHA1(k.AccessKeySecret, toSign))
+}
+
+// scope returns the V4 credential scope.
+func scope(now time.Time, region string) string {
+	return now.UTC().Format(V4Date) + "/" + region + "/oss/" + V4Request
+}
+
+// signingKey derives the V4 signing key.
+func (k *AccessKey) signingKey(now time.Time, region string) []byte {
+	key := hmacSHA256([]byte("aliyun_v4"+k.AccessKeySecret), now.UTC().Format(V4Date))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "oss")
+	return hmacSHA256(key, V4Request)
+}
+
+// canonicalQuery sorts the query params by the encoded keys
+// and encodes them, leaving out the '=' of empty values.
+func canonicalQuery(v url.Values) string {
+	keys := make([]string, 0, len(v))
+	encoded := make(map[string]string, len(v))
+	for key := range v {
+		ek := encode(key, false)
+		keys = append(keys, ek)
+		encoded[ek] = key
+	}
+	sort.Strings(keys)
+
+	ps := make([]string, len(keys))
+	for i, ek := range keys {
+		ps[i] = ek
+		if val := v.Get(encoded[ek]); val != "" {
+			ps[i] += "=" + encode(val, false)
+		}
+	}
+	return strings.Join(ps, "&")
+}
+
+// signatureV4 signs the canonical request of the resource
+// (/bucket/encoded-object), the query and the headers, which
+// are the lower-case content-type, content-md5 & x-oss-*
+// ones. No additional headers are signed and the payload
+// is unsigned.
+func (k *AccessKey) signatureV4(now time.Time, region, method, resource string, query url.Values, headers map[string]string) string {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var canonicalHeaders string
+	for _, key := range keys {
+		canonicalHeaders += key + ":" + strings.TrimSpace(headers[key]) + "\n"
+	}
+
+	// VERB + "\n" + CanonicalURI + "\n" + CanonicalQuery + "\n"
+	// + CanonicalHeaders + "\n" + AdditionalHeaders + "\n" + Payload
+	canonical := method + "\n" +
+		resource + "\n" +
+		canonicalQuery(query) + "\n" +
+		canonicalHeaders + "\n" +
+		"\n" +
+		V4Payload
+	sum := sha256.Sum256([]byte(canonical))
+
+	toSign := V4Algorithm + "\n" +
+		now.UTC().Format(V4DateTime) + "\n" +
+		scope(now, region) + "\n" +
+		hex.EncodeToString(sum[:])
+	return hex.EncodeToString(hmacSHA256(k.signingKey(now, region), toSign))
+}
+
+// SignV4 returns a presigned URL using the V4 signature.
+// The Expires is capped by MaxV4Expires.
+func (k *AccessKey) SignV4(o aliyun.OSS, p Presign) string {
+	return k.signV4(o, p, time.Now())
+}
+
+func (k *AccessKey) signV4(o aliyun.OSS, p Presign, now time.Time) string {
+	region := Region(o.Endpoint)
+	expires := p.expires()
+	if expires > MaxV4Expires {
+		expires = MaxV4Expires
+	}
+
+	v := url.Values{}
+	v.Set("x-oss-signature-version", V4Algorithm)
+	v.Set("x-oss-credential", k.AccessKeyID+"/"+scope(now, region))
+	v.Set("x-oss-date", now.UTC().Format(V4DateTime))
+	v.Set("x-oss-expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	if k.SecurityToken != "" {
+		v.Set("x-oss-security-token", k.SecurityToken)
+	}
+
+	headers := make(map[string]string)
+	if p.ContentType != "" {
+		headers["content-type"] = p.ContentType
+	}
+	resource := "/" + o.Bucket + "/" + encode(o.Object, true)
+	v.Set("x-oss-signature", k.signatureV4(now, region, p.method(), resource, v, headers))
+
+	return ObjectURL(o) + "?" + canonicalQuery(v)
+}
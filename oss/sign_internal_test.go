@@ -0,0 +1,140 @@
+package oss
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/practigo/aliyun"
+)
+
+// the example vectors are from the auth tests of the official
+// Go SDK (aliyun-oss-go-sdk v3.0.2, oss/conn_test.go)
+var sdkKey = NewAccessKey("ak", "sk")
+
+func TestSignatureV1(t *testing.T) {
+	k := &AccessKey{AccessKeyID: "ak", AccessKeySecret: "sk", SecurityToken: "token"}
+	for _, c := range []struct {
+		k        *AccessKey
+		expires  string
+		resource string
+		want     string
+	}{
+		{sdkKey, "1699807420", "/bucket/key?versionId=versionId", "dcLTea+Yh9ApirQ8o8dOPqtvJXQ="},
+		{k, "1699808204", "/bucket/key+123?security-token=token&versionId=versionId", "jzKYRrM5y6Br0dRFPaTGOsbrDhY="},
+	} {
+		if got := c.k.signatureV1("GET", "", c.expires, c.resource); got != c.want {
+			t.Errorf("signatureV1(%s) = %s, want %s", c.resource, got, c.want)
+		}
+	}
+}
+
+func TestSignatureV4(t *testing.T) {
+	query := url.Values{
+		"param1":  {"value1"},
+		"+param1": {"value3"},
+		"|param1": {"value4"},
+		"+param2": {""},
+		"|param2": {""},
+		"param2":  {""},
+	}
+	withQueryAuth := url.Values{
+		"x-oss-signature-version": {V4Algorithm},
+		"x-oss-credential":        {"ak/20231217/cn-hangzhou/oss/aliyun_v4_request"},
+		"x-oss-date":              {"20231217T025437Z"},
+		"x-oss-expires":           {"599"},
+	}
+	for k, v := range query {
+		withQueryAuth[k] = v
+	}
+
+	k := &AccessKey{AccessKeyID: "ak", AccessKeySecret: "sk", SecurityToken: "token"}
+	for name, c := range map[string]struct {
+		k       *AccessKey
+		now     int64
+		query   url.Values
+		headers map[string]string
+		want    string
+	}{
+		"query": {sdkKey, 1702781677, withQueryAuth, map[string]string{
+			"content-type": "application/octet-stream",
+			"x-oss-head1":  "value",
+		}, "a39966c61718be0d5b14e668088b3fa07601033f6518ac7b523100014269c0fe"},
+		"header": {sdkKey, 1702743657, query, map[string]string{
+			"content-type":         "text/plain",
+			"x-oss-head1":          "value",
+			"x-oss-content-sha256": V4Payload,
+			"x-oss-date":           "20231216T162057Z",
+		}, "e21d18daa82167720f9b1047ae7e7f1ce7cb77a31e8203a7d5f4624fa0284afe"},
+		"token": {k, 1702784856, query, map[string]string{
+			"content-type":         "text/plain",
+			"x-oss-head1":          "value",
+			"x-oss-content-sha256": V4Payload,
+			"x-oss-date":           "20231217T034736Z",
+			"x-oss-security-token": "token",
+		}, "b94a3f999cf85bcdc00d332fbd3734ba03e48382c36fa4d5af5df817395bd9ea"},
+	} {
+		got := c.k.signatureV4(time.Unix(c.now, 0), "cn-hangzhou", "PUT",
+			"/bucket/"+encode("1234+-/123/1.txt", true), c.query, c.headers)
+		if got != c.want {
+			t.Errorf("%s: signatureV4 = %s, want %s", name, got, c.want)
+		}
+	}
+}
+
+// the vectors below are computed independently of this
+// package, as there are no published ones
+
+var (
+	testNow = time.Unix(1702781677, 0) // 20231217T025437Z
+	testLoc = aliyun.OSS{
+		Bucket:   "bucket",
+		Endpoint: "oss-cn-hangzhou.aliyuncs.com",
+		Object:   "key",
+	}
+)
+
+func TestSignV1Exact(t *testing.T) {
+	got := sdkKey.signV1(testLoc, Presign{}, testNow)
+	want := "https://bucket.oss-cn-hangzhou.aliyuncs.com/key?" +
+		"Expires=1702785277&OSSAccessKeyId=ak&Signature=tsS%2BHH0v9XaF2IKQgywM8FNQdmM%3D"
+	if got != want {
+		t.Errorf("signV1 = %s, want %s", got, want)
+	}
+}
+
+func TestSignV4Exact(t *testing.T) {
+	k := &AccessKey{AccessKeyID: "ak", AccessKeySecret: "sk", SecurityToken: "token"}
+	o := testLoc
+	o.Object = "dir/a b.txt"
+	got := k.signV4(o, Presign{Method: "PUT", ContentType: "text/plain"}, testNow)
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "3770c63e4c9931b7d76dd480a7dfbe23e6b61d11104477dc2cfed1f76059c23b"
+	if s := u.Query().Get("x-oss-signature"); s != want {
+		t.Errorf("signV4 signature = %s, want %s in %s", s, want, got)
+	}
+}
+
+func TestPostExact(t *testing.T) {
+	form, err := sdkKey.postV1(testLoc, PostPolicy{}, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// {"conditions":[{"bucket":"bucket"},["eq","$key","key"]],"expiration":"2023-12-17T03:54:37.000Z"}
+	if want := "shdV3j6ui2bLIsgam+INBOgVPbo="; form.Fields["Signature"] != want {
+		t.Errorf("postV1 signature = %s, want %s", form.Fields["Signature"], want)
+	}
+
+	form, err = sdkKey.postV4(testLoc, PostPolicy{}, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the extra conditions are x-oss-credential, x-oss-date &
+	// x-oss-signature-version in order
+	if want := "483999b605390e93dcdeb36813322d4e20b5e4ab020dafed0af19944cb69af1b"; form.Fields["x-oss-signature"] != want {
+		t.Errorf("postV4 signature = %s, want %s", form.Fields["x-oss-signature"], want)
+	}
+}
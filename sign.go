@@ -25,6 +25,8 @@ type Signer interface {
 type AccessKey struct {
 	// user specific
 	id, secret string
+	// optional, for temporary credentials
+	token string
 	// internal, so far fixed
 	ver    string
 	method string
@@ -44,6 +46,9 @@ func (s *AccessKey) Sign(a API) string {
 	v.Set("SignatureVersion", s.ver)
 	v.Set("SignatureNonce", a.Nonce())
 	v.Set("Format", s.format)
+	if s.token != "" {
+		v.Set("SecurityToken", s.token)
+	}
 
 	// this also sort the params
 	query := v.Encode()
@@ -83,6 +88,15 @@ func NewAccessKey(id, secret string) *AccessKey {
 	}
 }
 
+// NewSTSAccessKey returns a AccessKey to sign the APIs
+// with temporary credentials obtained from STS. The
+// token is sent as the SecurityToken param.
+func NewSTSAccessKey(id, secret, token string) *AccessKey {
+	k := NewAccessKey(id, secret)
+	k.token = token
+	return k
+}
+
 // RandString returns a random string with the given
 // length n.
 func RandString(n int) string {
@@ -8,18 +8,61 @@ import (
 // A KeyFunc maps the param to a key string.
 type KeyFunc func(*AssumeRoleParam) string
 
-// A cache caches the credentials to descrease requests.
-type cache struct {
+// Hooks are the callbacks fired by a Cache. Any of
+// them can be nil. They are called synchronously in
+// the Get, so they should return quickly.
+type Hooks struct {
+	// OnRefresh is called after new credentials are obtained.
+	OnRefresh func(key string, cred Credentials)
+	// OnFailure is called after failing to get new credentials,
+	// with the number of consecutive failures for the key.
+	OnFailure func(key string, err error, failures int)
+	// OnDanger is called once for each credentials which will
+	// expire within the Danger window.
+	OnDanger func(key string, cred Credentials)
+	Danger   time.Duration
+}
+
+// KeyStats is the stats of a single key.
+type KeyStats struct {
+	Expiration time.Time
+	// TTL is the time to expiry when the stats is taken.
+	TTL time.Duration
+	// Failures is the number of consecutive failures.
+	Failures int
+}
+
+// Stats is the stats of a Cache. Hits are the requests
+// served by the cache (or store), Misses are the ones
+// passed to the underlying Getter, which then results in
+// either Refreshes or Failures.
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Refreshes int64
+	Failures  int64
+	Keys      map[string]KeyStats
+}
+
+// A Cache caches the credentials to descrease requests.
+type Cache struct {
 	g Getter
 	k KeyFunc
+	s Store // optional
 
 	// the internal creds map
 	mu sync.RWMutex
 	v  map[string]Credentials
+
+	// observability
+	hooks    Hooks
+	stats    Stats
+	failures map[string]int
+	warned   map[string]time.Time // expiration warned
 }
 
 // get from the map
-func (c *cache) get(key string) (Credentials, bool) {
+func (c *Cache) get(key string) (Credentials, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	cred, ok := c.v[key]
@@ -27,19 +70,34 @@ func (c *cache) get(key string) (Credentials, bool) {
 }
 
 // set to the map
-func (c *cache) set(key string, cred Credentials) {
+func (c *Cache) set(key string, cred Credentials) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.v[key] = cred
 }
 
-func (c *cache) Get(p *AssumeRoleParam, dur int64) (cred Credentials, err error) {
+// valid checks if the cred is not expired after dur seconds.
+func valid(cred Credentials, dur int64) bool {
+	return cred.Expiration.After(time.Now().Add(time.Duration(dur) * time.Second))
+}
+
+// Get gets the credentials from the cache if they won't
+// expire in dur seconds, or from the underlying Getter.
+func (c *Cache) Get(p *AssumeRoleParam, dur int64) (cred Credentials, err error) {
 	key := c.k(p)
 
 	cred, ok := c.get(key)
-	if ok {
-		// check if it's expired after dur seconds
-		if cred.Expiration.After(time.Now().Add(time.Duration(dur) * time.Second)) {
+	if ok && valid(cred, dur) {
+		c.hit(key, cred)
+		return
+	}
+
+	// then try the store
+	if c.s != nil {
+		cred, ok = c.s.Load(key)
+		if ok && valid(cred, dur) {
+			c.set(key, cred)
+			c.hit(key, cred)
 			return
 		}
 	}
@@ -47,22 +105,144 @@ func (c *cache) Get(p *AssumeRoleParam, dur int64) (cred Credentials, err error)
 	// not exist or need update
 	cred, err = c.g.Get(p, 0)
 	if err != nil {
+		c.fail(key, err)
 		return
 	}
 
 	// update credential
 	c.set(key, cred)
+	if c.s != nil {
+		// failing to persist is not fatal
+		c.s.Save(key, cred)
+	}
+	c.refresh(key, cred)
 	return
 }
 
+func (c *Cache) hit(key string, cred Credentials) {
+	c.mu.Lock()
+	c.stats.Hits++
+	h := c.hooks
+	c.mu.Unlock()
+
+	c.check(h, key, cred)
+}
+
+func (c *Cache) fail(key string, err error) {
+	c.mu.Lock()
+	c.stats.Misses++
+	c.stats.Failures++
+	c.failures[key]++
+	n := c.failures[key]
+	h := c.hooks
+	c.mu.Unlock()
+
+	if h.OnFailure != nil {
+		h.OnFailure(key, err, n)
+	}
+}
+
+func (c *Cache) refresh(key string, cred Credentials) {
+	c.mu.Lock()
+	c.stats.Misses++
+	c.stats.Refreshes++
+	delete(c.failures, key)
+	h := c.hooks
+	c.mu.Unlock()
+
+	if h.OnRefresh != nil {
+		h.OnRefresh(key, cred)
+	}
+	c.check(h, key, cred)
+}
+
+// check fires OnDanger once if the cred is in the danger window.
+func (c *Cache) check(h Hooks, key string, cred Credentials) {
+	if h.OnDanger == nil || time.Until(cred.Expiration) > h.Danger {
+		return
+	}
+
+	c.mu.Lock()
+	warned := c.warned[key].Equal(cred.Expiration)
+	c.warned[key] = cred.Expiration
+	c.mu.Unlock()
+
+	if !warned {
+		h.OnDanger(key, cred)
+	}
+}
+
+// Check checks all the cached credentials for the danger
+// window. Call it periodically to be notified even if there
+// is no Get for a key.
+func (c *Cache) Check() {
+	c.mu.RLock()
+	h := c.hooks
+	creds := make(map[string]Credentials, len(c.v))
+	for k, cred := range c.v {
+		creds[k] = cred
+	}
+	c.mu.RUnlock()
+
+	for k, cred := range creds {
+		c.check(h, k, cred)
+	}
+}
+
+// SetHooks sets the callbacks.
+func (c *Cache) SetHooks(h Hooks) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = h
+}
+
+// Stats returns a snapshot of the stats.
+func (c *Cache) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s := c.stats
+	s.Keys = make(map[string]KeyStats, len(c.v))
+	now := time.Now()
+	for k, cred := range c.v {
+		s.Keys[k] = KeyStats{
+			Expiration: cred.Expiration,
+			TTL:        cred.Expiration.Sub(now),
+			Failures:   c.failures[k],
+		}
+	}
+	for k, n := range c.failures {
+		if _, ok := s.Keys[k]; !ok {
+			s.Keys[k] = KeyStats{Failures: n}
+		}
+	}
+	return s
+}
+
 // Wrap wraps a Getter to enable caching.
 // If the provide keyFunc k is nil, the
 // DefaultKey is used.
 func Wrap(g Getter, k KeyFunc) Getter {
-	c := &cache{
-		g: g,
-		k: k,
-		v: make(map[string]Credentials),
+	return NewCache(g, k, nil)
+}
+
+// WrapStore is like Wrap but also consults the Store s
+// before requesting new credentials, and saves them
+// to s afterwards. A nil s disables the store.
+func WrapStore(g Getter, k KeyFunc, s Store) Getter {
+	return NewCache(g, k, s)
+}
+
+// NewCache is like WrapStore but returns the *Cache
+// for the Stats & Hooks.
+func NewCache(g Getter, k KeyFunc, s Store) *Cache {
+	c := &Cache{
+		g:        g,
+		k:        k,
+		s:        s,
+		v:        make(map[string]Credentials),
+		failures: make(map[string]int),
+		warned:   make(map[string]time.Time),
 	}
 	if c.k == nil {
 		c.k = DefaultKey
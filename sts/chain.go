@@ -0,0 +1,63 @@
+package sts
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/practigo/aliyun"
+)
+
+// chainMargin is the min lifetime (in seconds) of the
+// credentials of an intermediate stage, so that they
+// would not expire while signing the next request.
+const chainMargin = 60
+
+// Signer returns an aliyun.Signer that signs the APIs
+// with the credentials (including the SecurityToken).
+func (c Credentials) Signer() aliyun.Signer {
+	return aliyun.NewSTSAccessKey(c.AccessKeyID, c.AccessKeySecret, c.SecurityToken)
+}
+
+// A relay assumes a role using the credentials of
+// the previous stage.
+type relay struct {
+	prev Getter
+	p    *AssumeRoleParam
+	host string
+	cl   *http.Client
+}
+
+func (r *relay) Get(p *AssumeRoleParam, dur int64) (cred Credentials, err error) {
+	prev, err := r.prev.Get(r.p, chainMargin)
+	if err != nil {
+		return
+	}
+	g := &getter{
+		s:    prev.Signer(),
+		host: r.host,
+		cl:   r.cl,
+	}
+	return g.Get(p, dur)
+}
+
+// Chain returns a Getter that assumes the roles in via one
+// after another, starting with the Signer s, and then uses
+// the credentials of the last one to assume the role
+// requested by Get. This is typical for cross-account
+// access, e.g., assume role A in our account and then
+// role B in a customer account.
+//
+// Each stage is cached by the KeyFunc k (DefaultKey if nil),
+// so only the expired stages are requested again.
+func Chain(s aliyun.Signer, host string, k KeyFunc, via ...*AssumeRoleParam) Getter {
+	g := Wrap(New(s, host), k)
+	for _, p := range via {
+		g = Wrap(&relay{
+			prev: g,
+			p:    p,
+			host: host,
+			cl:   aliyun.TimeoutClient(5 * time.Second),
+		}, k)
+	}
+	return g
+}
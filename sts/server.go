@@ -0,0 +1,110 @@
+package sts
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/practigo/aliyun"
+)
+
+// Paths served by a Server.
+const (
+	// MetadataPath mimics the ECS instance metadata service.
+	MetadataPath = "/latest/meta-data/ram/security-credentials/"
+	// CredentialsPath serves the credentials URI format.
+	CredentialsPath = "/credentials/"
+)
+
+// A MetadataCredentials is the credentials in the ECS
+// metadata format; the credentials URI format is the
+// same without LastUpdated.
+type MetadataCredentials struct {
+	Code            string `json:"Code"`
+	Message         string `json:"Message,omitempty"`
+	AccessKeyID     string `json:"AccessKeyId,omitempty"`
+	AccessKeySecret string `json:"AccessKeySecret,omitempty"`
+	SecurityToken   string `json:"SecurityToken,omitempty"`
+	Expiration      string `json:"Expiration,omitempty"`
+	LastUpdated     string `json:"LastUpdated,omitempty"`
+}
+
+// A Server vends the credentials of the Roles over HTTP,
+// so that any SDK-based tool pointed at it receives
+// auto-refreshed temporary credentials. Roles are served at
+// MetadataPath+name and CredentialsPath+name, and
+// MetadataPath itself lists the role names.
+//
+// The Getter is expected to be cached, e.g., by Wrap.
+type Server struct {
+	Getter Getter
+	Roles  map[string]*AssumeRoleParam
+	// Duration is the min lifetime (in seconds) of the
+	// served credentials, 300 if not set.
+	Duration int64
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case r.URL.Path == MetadataPath:
+		s.list(w)
+	case strings.HasPrefix(r.URL.Path, MetadataPath):
+		s.serve(w, strings.TrimPrefix(r.URL.Path, MetadataPath), true)
+	case strings.HasPrefix(r.URL.Path, CredentialsPath):
+		s.serve(w, strings.TrimPrefix(r.URL.Path, CredentialsPath), false)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter) {
+	names := make([]string, 0, len(s.Roles))
+	for name := range s.Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w.Write([]byte(strings.Join(names, "\n")))
+}
+
+func (s *Server) serve(w http.ResponseWriter, name string, metadata bool) {
+	p, ok := s.Roles[name]
+	if !ok {
+		http.Error(w, "unknown role "+name, http.StatusNotFound)
+		return
+	}
+
+	dur := s.Duration
+	if dur <= 0 {
+		dur = 300
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	cred, err := s.Getter.Get(p, dur)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(MetadataCredentials{
+			Code:    "Failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	resp := MetadataCredentials{
+		Code:            "Success",
+		AccessKeyID:     cred.AccessKeyID,
+		AccessKeySecret: cred.AccessKeySecret,
+		SecurityToken:   cred.SecurityToken,
+		Expiration:      aliyun.FormatT(cred.Expiration),
+	}
+	if metadata {
+		resp.LastUpdated = aliyun.FormatT(time.Now())
+	}
+	json.NewEncoder(w).Encode(resp)
+}
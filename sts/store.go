@@ -0,0 +1,95 @@
+package sts
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// A Store persists the credentials beyond the process,
+// so that short-lived processes can reuse them.
+type Store interface {
+	// Load returns the credentials saved for the key.
+	Load(key string) (Credentials, bool)
+	// Save saves the credentials for the key.
+	Save(key string, cred Credentials) error
+}
+
+// A FileStore stores each credentials as a JSON file
+// in the directory Dir, which is only accessible by
+// the current user.
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore returns a FileStore using the dir.
+// If dir is empty, it defaults to the "aliyun-sts"
+// sub-directory of the user cache dir.
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(cache, "aliyun-sts")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+// path returns the file path for the key. The key is
+// hashed since it may contain the policy.
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("%x.json", sha1.Sum([]byte(key))))
+}
+
+// Load reads the credentials from the file of the key.
+// Any error is treated as not found.
+func (s *FileStore) Load(key string) (cred Credentials, ok bool) {
+	bs, err := ioutil.ReadFile(s.path(key))
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(bs, &cred); err != nil {
+		return
+	}
+	return cred, true
+}
+
+// Save writes the credentials to a temp file with 0600
+// permissions and then renames it to the file of the key,
+// so that a reader never sees a partial file.
+func (s *FileStore) Save(key string, cred Credentials) (err error) {
+	bs, err := json.Marshal(cred)
+	if err != nil {
+		return
+	}
+
+	f, err := ioutil.TempFile(s.Dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	if err = f.Chmod(0600); err != nil {
+		f.Close()
+		return
+	}
+	if _, err = f.Write(bs); err != nil {
+		f.Close()
+		return
+	}
+	if err = f.Close(); err != nil {
+		return
+	}
+	return os.Rename(f.Name(), s.path(key))
+}
@@ -1,8 +1,14 @@
 package sts_test
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/practigo/aliyun"
 	"github.com/practigo/aliyun/sts"
@@ -73,3 +79,190 @@ func TestCache(t *testing.T) {
 		t.Logf("%+v", cred)
 	}
 }
+
+// fakeSTS returns a server that issues credentials named after
+// the requested role, recording the SecurityToken used.
+func fakeSTS(tokens *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		*tokens = append(*tokens, q.Get("SecurityToken"))
+		role := q.Get("RoleArn")
+		json.NewEncoder(w).Encode(sts.AssumeRoleResponse{
+			RequestID: "fake",
+			Cred: sts.Credentials{
+				AccessKeyID:     "id-" + role,
+				AccessKeySecret: "secret-" + role,
+				SecurityToken:   "token-" + role,
+				Expiration:      time.Now().Add(time.Hour).UTC(),
+			},
+		})
+	}))
+}
+
+func TestChain(t *testing.T) {
+	var tokens []string
+	srv := fakeSTS(&tokens)
+	defer srv.Close()
+
+	s := aliyun.NewAccessKey("id", "secret")
+	g := sts.Chain(s, srv.URL, nil,
+		&sts.AssumeRoleParam{RoleArn: "a", RoleSessionName: "s"},
+		&sts.AssumeRoleParam{RoleArn: "b", RoleSessionName: "s"},
+	)
+
+	final := &sts.AssumeRoleParam{RoleArn: "c", RoleSessionName: "s"}
+	cred, err := g.Get(final, 900)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.AccessKeyID != "id-c" {
+		t.Errorf("want credentials for role c, got %+v", cred)
+	}
+	want := []string{"", "token-a", "token-b"}
+	if strings.Join(tokens, ",") != strings.Join(want, ",") {
+		t.Errorf("want tokens %v, got %v", want, tokens)
+	}
+
+	// all stages are cached
+	if _, err = g.Get(final, 900); err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != len(want) {
+		t.Errorf("want %d requests, got %d", len(want), len(tokens))
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sts-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := sts.NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tokens []string
+	srv := fakeSTS(&tokens)
+	defer srv.Close()
+
+	s := aliyun.NewAccessKey("id", "secret")
+	param := &sts.AssumeRoleParam{RoleArn: "a", RoleSessionName: "s"}
+
+	// two "processes" sharing the same store
+	for i := 0; i < 2; i++ {
+		g := sts.WrapStore(sts.New(s, srv.URL), nil, store)
+		cred, err := g.Get(param, 900)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cred.AccessKeyID != "id-a" {
+			t.Errorf("want credentials for role a, got %+v", cred)
+		}
+	}
+	if len(tokens) != 1 {
+		t.Errorf("want 1 request, got %d", len(tokens))
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("want 1 file, got %d", len(files))
+	}
+	if perm := files[0].Mode().Perm(); perm != 0600 {
+		t.Errorf("want permissions 0600, got %o", perm)
+	}
+}
+
+func TestServer(t *testing.T) {
+	var tokens []string
+	fake := fakeSTS(&tokens)
+	defer fake.Close()
+
+	s := aliyun.NewAccessKey("id", "secret")
+	srv := httptest.NewServer(&sts.Server{
+		Getter: sts.Wrap(sts.New(s, fake.URL), nil),
+		Roles: map[string]*sts.AssumeRoleParam{
+			"dev": {RoleArn: "a", RoleSessionName: "s"},
+		},
+	})
+	defer srv.Close()
+
+	for _, path := range []string{sts.MetadataPath + "dev", sts.CredentialsPath + "dev"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cred sts.MetadataCredentials
+		err = json.NewDecoder(resp.Body).Decode(&cred)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cred.Code != "Success" || cred.AccessKeyID != "id-a" {
+			t.Errorf("%s: unexpected credentials %+v", path, cred)
+		}
+	}
+	if len(tokens) != 1 {
+		t.Errorf("want 1 request, got %d", len(tokens))
+	}
+
+	resp, err := http.Get(srv.URL + sts.MetadataPath + "unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("want 404 for unknown role, got %d", resp.StatusCode)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	var tokens []string
+	srv := fakeSTS(&tokens)
+	defer srv.Close()
+
+	s := aliyun.NewAccessKey("id", "secret")
+	c := sts.NewCache(sts.New(s, srv.URL), nil, nil)
+
+	var refreshed, dangers, failures int
+	c.SetHooks(sts.Hooks{
+		OnRefresh: func(string, sts.Credentials) { refreshed++ },
+		OnFailure: func(string, error, int) { failures++ },
+		OnDanger:  func(string, sts.Credentials) { dangers++ },
+		Danger:    2 * time.Hour, // fake ones expire in 1h
+	})
+
+	param := &sts.AssumeRoleParam{RoleArn: "a", RoleSessionName: "s"}
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(param, 900); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.Check()
+
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Refreshes != 1 || stats.Failures != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if ks := stats.Keys[sts.DefaultKey(param)]; ks.TTL <= 0 {
+		t.Errorf("unexpected key stats %+v", ks)
+	}
+	if refreshed != 1 || dangers != 1 || failures != 0 {
+		t.Errorf("unexpected callbacks: %d refreshed, %d dangers, %d failures",
+			refreshed, dangers, failures)
+	}
+
+	// make it fail
+	srv.Close()
+	if _, err := c.Get(&sts.AssumeRoleParam{RoleArn: "b"}, 900); err == nil {
+		t.Error("should fail with closed server")
+	}
+	if failures != 1 || c.Stats().Failures != 1 {
+		t.Errorf("want 1 failure, got %d", failures)
+	}
+}